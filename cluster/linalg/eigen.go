@@ -0,0 +1,171 @@
+// Package linalg provides the dense linear algebra routines shared by the
+// coordinator and the workers that go.matrix either lacks or does not
+// implement deterministically
+package linalg
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	matrix "github.com/skelterjohn/go.matrix"
+)
+
+const (
+	maxSweeps       = 100
+	symmetryEpsilon = 1e-9
+)
+
+// SymmetricEigen computes the eigenvalues and eigenvectors of a symmetric
+// matrix using the cyclic Jacobi method. The eigenvalues are returned in
+// descending order and the eigenvectors are the matching columns of V. Each
+// eigenvector is oriented so that its largest magnitude component is positive,
+// which makes the result independent of the order in which the matrix was
+// accumulated
+func SymmetricEigen(m *matrix.DenseMatrix) (*matrix.DenseMatrix, []float64, error) {
+	n, cols := m.GetSize()
+	if n != cols {
+		return nil, nil, errors.New("Matrix is not square")
+	}
+
+	a := m.Copy().Arrays()
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			scale := math.Max(1, math.Max(math.Abs(a[i][j]), math.Abs(a[j][i])))
+			if math.Abs(a[i][j]-a[j][i]) > symmetryEpsilon*scale {
+				return nil, nil, errors.New("Matrix is not symmetric")
+			}
+		}
+	}
+
+	v := make([][]float64, n)
+	for i := range v {
+		v[i] = make([]float64, n)
+		v[i][i] = 1
+	}
+
+	converged := false
+	for sweep := 0; sweep < maxSweeps; sweep++ {
+		var off, diag float64
+		for i := 0; i < n; i++ {
+			diag += a[i][i] * a[i][i]
+			for j := i + 1; j < n; j++ {
+				off += a[i][j] * a[i][j]
+			}
+		}
+		if off == 0 || off <= 1e-30*diag {
+			converged = true
+			break
+		}
+
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+				rotate(a, v, p, q)
+			}
+		}
+	}
+	if !converged {
+		return nil, nil, errors.New("Jacobi eigenvalue algorithm did not converge")
+	}
+
+	order := &descending{
+		indices: make([]int, n),
+		values:  make([]float64, n),
+	}
+	for i := 0; i < n; i++ {
+		order.indices[i] = i
+		order.values[i] = a[i][i]
+	}
+	sort.Stable(order)
+
+	values := make([]float64, n)
+	vectors := matrix.Zeros(n, n)
+	for k, col := range order.indices {
+		values[k] = a[col][col]
+		for i := 0; i < n; i++ {
+			vectors.Set(i, k, v[i][col])
+		}
+	}
+	OrientColumns(vectors)
+
+	return vectors, values, nil
+}
+
+// rotate applies the Jacobi rotation that zeroes a[p][q], accumulating the
+// rotation into the columns of v
+func rotate(a, v [][]float64, p, q int) {
+	n := len(a)
+	theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+	t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+	if theta < 0 {
+		t = -t
+	}
+	c := 1 / math.Sqrt(t*t+1)
+	s := t * c
+
+	for k := 0; k < n; k++ {
+		akp := a[k][p]
+		akq := a[k][q]
+		a[k][p] = c*akp - s*akq
+		a[k][q] = s*akp + c*akq
+	}
+	for k := 0; k < n; k++ {
+		apk := a[p][k]
+		aqk := a[q][k]
+		a[p][k] = c*apk - s*aqk
+		a[q][k] = s*apk + c*aqk
+	}
+	for k := 0; k < n; k++ {
+		vkp := v[k][p]
+		vkq := v[k][q]
+		v[k][p] = c*vkp - s*vkq
+		v[k][q] = s*vkp + c*vkq
+	}
+}
+
+// OrientColumns flips the sign of each column of m so that its largest
+// magnitude component is positive and returns the sign applied to each
+// column. Ties are broken by the lowest row index
+func OrientColumns(m *matrix.DenseMatrix) []float64 {
+	rows, cols := m.GetSize()
+	signs := make([]float64, cols)
+	for j := 0; j < cols; j++ {
+		signs[j] = 1
+		largest := 0
+		for i := 0; i < rows; i++ {
+			if math.Abs(m.Get(i, j)) > math.Abs(m.Get(largest, j))+symmetryEpsilon {
+				largest = i
+			}
+		}
+		if m.Get(largest, j) >= 0 {
+			continue
+		}
+		signs[j] = -1
+		for i := 0; i < rows; i++ {
+			m.Set(i, j, -m.Get(i, j))
+		}
+	}
+	return signs
+}
+
+// descending sorts indices by their associated values, largest first
+type descending struct {
+	indices []int
+	values  []float64
+}
+
+func (d *descending) Len() int {
+	return len(d.indices)
+}
+
+func (d *descending) Less(i, j int) bool {
+	return d.values[i] > d.values[j]
+}
+
+func (d *descending) Swap(i, j int) {
+	d.indices[i], d.indices[j] = d.indices[j], d.indices[i]
+	d.values[i], d.values[j] = d.values[j], d.values[i]
+}
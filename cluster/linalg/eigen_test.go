@@ -0,0 +1,166 @@
+package linalg
+
+import (
+	"math"
+	"testing"
+
+	matrix "github.com/skelterjohn/go.matrix"
+)
+
+const testEpsilon = 1e-9
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) <= testEpsilon*math.Max(1, math.Abs(b))
+}
+
+// nearMatrix returns whether two matrices have the same size and elements
+func nearMatrix(a, b *matrix.DenseMatrix) bool {
+	if a.Rows() != b.Rows() || a.Cols() != b.Cols() {
+		return false
+	}
+	for i := 0; i < a.Rows(); i++ {
+		for j := 0; j < a.Cols(); j++ {
+			if !near(a.Get(i, j), b.Get(i, j)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// orthonormalColumns returns whether the columns of m are orthonormal
+func orthonormalColumns(m *matrix.DenseMatrix) bool {
+	gram, err := m.Transpose().TimesDense(m)
+	if err != nil {
+		return false
+	}
+	return nearMatrix(gram, matrix.Eye(m.Cols()))
+}
+
+func TestSymmetricEigen(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       [][]float64
+		values  []float64
+		vectors [][]float64 // nil when not unique
+	}{
+		{
+			name:    "diagonal",
+			m:       [][]float64{{1, 0, 0}, {0, 3, 0}, {0, 0, 2}},
+			values:  []float64{3, 2, 1},
+			vectors: [][]float64{{0, 0, 1}, {1, 0, 0}, {0, 1, 0}},
+		},
+		{
+			name:   "two by two",
+			m:      [][]float64{{2, 1}, {1, 2}},
+			values: []float64{3, 1},
+			vectors: [][]float64{
+				{1 / math.Sqrt2, 1 / math.Sqrt2},
+				{1 / math.Sqrt2, -1 / math.Sqrt2},
+			},
+		},
+		{
+			name:   "rank deficient",
+			m:      [][]float64{{1, 1, 1}, {1, 1, 1}, {1, 1, 1}},
+			values: []float64{3, 0, 0},
+		},
+		{
+			name:   "empty",
+			m:      nil,
+			values: []float64{},
+		},
+	}
+
+	for _, test := range tests {
+		m := matrix.Zeros(0, 0)
+		if len(test.m) > 0 {
+			m = matrix.MakeDenseMatrixStacked(test.m)
+		}
+		vectors, values, err := SymmetricEigen(m)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if len(test.m) > 0 && !nearMatrix(m, matrix.MakeDenseMatrixStacked(test.m)) {
+			t.Errorf("%s: input matrix was modified", test.name)
+		}
+		if len(values) != len(test.values) {
+			t.Errorf("%s: got %d eigenvalues, want %d", test.name, len(values), len(test.values))
+			continue
+		}
+		for k := range values {
+			if !near(values[k], test.values[k]) {
+				t.Errorf("%s: eigenvalue %d is %v, want %v", test.name, k, values[k], test.values[k])
+			}
+		}
+		if !orthonormalColumns(vectors) {
+			t.Errorf("%s: eigenvectors are not orthonormal", test.name)
+		}
+		if test.vectors != nil && !nearMatrix(vectors, matrix.MakeDenseMatrixStacked(test.vectors)) {
+			t.Errorf("%s: eigenvectors are %v, want %v", test.name, vectors, test.vectors)
+		}
+
+		// V diag(values) V^T reconstructs the matrix
+		scaled := vectors.Copy()
+		for i := 0; i < scaled.Rows(); i++ {
+			for k := range values {
+				scaled.Set(i, k, scaled.Get(i, k)*values[k])
+			}
+		}
+		product, err := scaled.TimesDense(vectors.Transpose())
+		if err != nil || !nearMatrix(product, m) {
+			t.Errorf("%s: eigenvectors do not reconstruct the matrix", test.name)
+		}
+	}
+}
+
+func TestSymmetricEigenInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *matrix.DenseMatrix
+	}{
+		{"not square", matrix.Zeros(2, 3)},
+		{"not symmetric", matrix.MakeDenseMatrixStacked([][]float64{{1, 2}, {0, 1}})},
+	}
+
+	for _, test := range tests {
+		if _, _, err := SymmetricEigen(test.m); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestOrientColumns(t *testing.T) {
+	tests := []struct {
+		name  string
+		m     [][]float64
+		want  [][]float64
+		signs []float64
+	}{
+		{
+			name:  "largest negative",
+			m:     [][]float64{{0.6, 1}, {-0.8, 0}},
+			want:  [][]float64{{-0.6, 1}, {0.8, 0}},
+			signs: []float64{-1, 1},
+		},
+		{
+			name:  "tie broken by first row",
+			m:     [][]float64{{-1, 1}, {1, -1}},
+			want:  [][]float64{{1, 1}, {-1, -1}},
+			signs: []float64{-1, 1},
+		},
+	}
+
+	for _, test := range tests {
+		m := matrix.MakeDenseMatrixStacked(test.m)
+		signs := OrientColumns(m)
+		if !nearMatrix(m, matrix.MakeDenseMatrixStacked(test.want)) {
+			t.Errorf("%s: oriented columns are %v, want %v", test.name, m, test.want)
+		}
+		for j := range signs {
+			if signs[j] != test.signs[j] {
+				t.Errorf("%s: sign of column %d is %v, want %v", test.name, j, signs[j], test.signs[j])
+			}
+		}
+	}
+}
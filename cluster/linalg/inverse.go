@@ -0,0 +1,56 @@
+package linalg
+
+import (
+	"math"
+
+	matrix "github.com/skelterjohn/go.matrix"
+)
+
+// pseudoInverseEpsilon is the fraction of the largest eigenvalue below which
+// eigenvalues are treated as zero when inverting
+const pseudoInverseEpsilon = 1e-12
+
+// PseudoInverse returns the Moore-Penrose pseudo-inverse of a symmetric
+// matrix from its eigen-decomposition. Directions with eigenvalues that are
+// negligible relative to the largest are dropped rather than inverted, so the
+// result is well defined for singular matrices
+func PseudoInverse(m *matrix.DenseMatrix) (*matrix.DenseMatrix, error) {
+	return invert(m, func(value float64) float64 { return value })
+}
+
+// PseudoInverseSqrt returns the symmetric square root of the pseudo-inverse
+// of a symmetric positive semi-definite matrix, which whitens data whose
+// scatter matrix it is. Negligible eigenvalues are dropped as in PseudoInverse
+func PseudoInverseSqrt(m *matrix.DenseMatrix) (*matrix.DenseMatrix, error) {
+	return invert(m, math.Sqrt)
+}
+
+// invert returns the sum of v v^T / f(value) over the eigenvectors v of a
+// symmetric matrix whose eigenvalues are not negligible
+func invert(m *matrix.DenseMatrix, f func(float64) float64) (*matrix.DenseMatrix, error) {
+	vectors, values, err := SymmetricEigen(m)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(values)
+	inverse := matrix.Zeros(n, n)
+	if n == 0 || values[0] <= 0 {
+		return inverse, nil
+	}
+	for k, value := range values {
+		if value <= pseudoInverseEpsilon*values[0] {
+			break
+		}
+		for i := 0; i < n; i++ {
+			scaled := vectors.Get(i, k) / f(value)
+			for j := i; j < n; j++ {
+				sum := inverse.Get(i, j) + scaled*vectors.Get(j, k)
+				inverse.Set(i, j, sum)
+				inverse.Set(j, i, sum)
+			}
+		}
+	}
+
+	return inverse, nil
+}
@@ -0,0 +1,50 @@
+package linalg
+
+import (
+	"testing"
+
+	matrix "github.com/skelterjohn/go.matrix"
+)
+
+func TestPseudoInverse(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       [][]float64
+		inverse [][]float64
+		sqrt    [][]float64
+	}{
+		{
+			name:    "diagonal",
+			m:       [][]float64{{4, 0}, {0, 0.25}},
+			inverse: [][]float64{{0.25, 0}, {0, 4}},
+			sqrt:    [][]float64{{0.5, 0}, {0, 2}},
+		},
+		{
+			name:    "singular",
+			m:       [][]float64{{1, 1}, {1, 1}},
+			inverse: [][]float64{{0.25, 0.25}, {0.25, 0.25}},
+			sqrt:    [][]float64{{0.35355339059327373, 0.35355339059327373}, {0.35355339059327373, 0.35355339059327373}},
+		},
+		{
+			name:    "zero",
+			m:       [][]float64{{0, 0}, {0, 0}},
+			inverse: [][]float64{{0, 0}, {0, 0}},
+			sqrt:    [][]float64{{0, 0}, {0, 0}},
+		},
+	}
+
+	for _, test := range tests {
+		m := matrix.MakeDenseMatrixStacked(test.m)
+		inverse, err := PseudoInverse(m)
+		if err != nil || !nearMatrix(inverse, matrix.MakeDenseMatrixStacked(test.inverse)) {
+			t.Errorf("%s: pseudo-inverse is %v, want %v (%v)", test.name, inverse, test.inverse, err)
+		}
+		sqrt, err := PseudoInverseSqrt(m)
+		if err != nil || !nearMatrix(sqrt, matrix.MakeDenseMatrixStacked(test.sqrt)) {
+			t.Errorf("%s: pseudo-inverse square root is %v, want %v (%v)", test.name, sqrt, test.sqrt, err)
+		}
+		if !nearMatrix(m, matrix.MakeDenseMatrixStacked(test.m)) {
+			t.Errorf("%s: input matrix was modified", test.name)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package linalg
+
+import (
+	"errors"
+	"math"
+
+	matrix "github.com/skelterjohn/go.matrix"
+)
+
+// QR computes the thin QR decomposition of a matrix with at least as many rows
+// as columns using Householder reflections. Q has orthonormal columns and R is
+// upper triangular with a non-negative diagonal so that the factorization is
+// unique for full rank input
+func QR(m *matrix.DenseMatrix) (*matrix.DenseMatrix, *matrix.DenseMatrix, error) {
+	rows, cols := m.GetSize()
+	if rows < cols {
+		return nil, nil, errors.New("QR requires at least as many rows as columns")
+	}
+
+	a := m.Copy().Arrays()
+	reflectors := make([][]float64, cols)
+	for k := 0; k < cols; k++ {
+		var norm float64
+		for i := k; i < rows; i++ {
+			norm += a[i][k] * a[i][k]
+		}
+		norm = math.Sqrt(norm)
+
+		v := make([]float64, rows-k)
+		for i := k; i < rows; i++ {
+			v[i-k] = a[i][k]
+		}
+		if v[0] < 0 {
+			v[0] -= norm
+		} else {
+			v[0] += norm
+		}
+		var vnorm float64
+		for _, x := range v {
+			vnorm += x * x
+		}
+		vnorm = math.Sqrt(vnorm)
+		if vnorm == 0 {
+			continue
+		}
+		for i := range v {
+			v[i] /= vnorm
+		}
+		reflectors[k] = v
+
+		for j := k; j < cols; j++ {
+			var dot float64
+			for i := k; i < rows; i++ {
+				dot += v[i-k] * a[i][j]
+			}
+			for i := k; i < rows; i++ {
+				a[i][j] -= 2 * dot * v[i-k]
+			}
+		}
+	}
+
+	r := matrix.Zeros(cols, cols)
+	for i := 0; i < cols; i++ {
+		for j := i; j < cols; j++ {
+			r.Set(i, j, a[i][j])
+		}
+	}
+
+	q := make([][]float64, rows)
+	for i := range q {
+		q[i] = make([]float64, cols)
+		if i < cols {
+			q[i][i] = 1
+		}
+	}
+	for k := cols - 1; k >= 0; k-- {
+		v := reflectors[k]
+		if v == nil {
+			continue
+		}
+		for j := 0; j < cols; j++ {
+			var dot float64
+			for i := k; i < rows; i++ {
+				dot += v[i-k] * q[i][j]
+			}
+			for i := k; i < rows; i++ {
+				q[i][j] -= 2 * dot * v[i-k]
+			}
+		}
+	}
+
+	for i := 0; i < cols; i++ {
+		if r.Get(i, i) >= 0 {
+			continue
+		}
+		for j := i; j < cols; j++ {
+			r.Set(i, j, -r.Get(i, j))
+		}
+		for j := 0; j < rows; j++ {
+			q[j][i] = -q[j][i]
+		}
+	}
+
+	return matrix.MakeDenseMatrixStacked(q), r, nil
+}
@@ -0,0 +1,59 @@
+package linalg
+
+import (
+	"testing"
+
+	matrix "github.com/skelterjohn/go.matrix"
+)
+
+func TestQR(t *testing.T) {
+	tests := []struct {
+		name string
+		m    [][]float64
+		r    [][]float64
+	}{
+		{
+			name: "square",
+			m:    [][]float64{{3, 1}, {4, 2}},
+			r:    [][]float64{{5, 2.2}, {0, 0.4}},
+		},
+		{
+			name: "negative diagonal",
+			m:    [][]float64{{-2, 0}, {0, -3}, {0, 0}},
+			r:    [][]float64{{2, 0}, {0, 3}},
+		},
+		{
+			name: "tall",
+			m:    [][]float64{{1, 0}, {1, 1}, {1, 2}, {1, 3}},
+			r:    [][]float64{{2, 3}, {0, 2.23606797749979}},
+		},
+	}
+
+	for _, test := range tests {
+		m := matrix.MakeDenseMatrixStacked(test.m)
+		q, r, err := QR(m)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if !nearMatrix(m, matrix.MakeDenseMatrixStacked(test.m)) {
+			t.Errorf("%s: input matrix was modified", test.name)
+		}
+		if !nearMatrix(r, matrix.MakeDenseMatrixStacked(test.r)) {
+			t.Errorf("%s: R is %v, want %v", test.name, r, test.r)
+		}
+		if !orthonormalColumns(q) {
+			t.Errorf("%s: Q does not have orthonormal columns", test.name)
+		}
+		product, err := q.TimesDense(r)
+		if err != nil || !nearMatrix(product, m) {
+			t.Errorf("%s: QR does not reconstruct the matrix", test.name)
+		}
+	}
+}
+
+func TestQRWide(t *testing.T) {
+	if _, _, err := QR(matrix.Zeros(2, 3)); err == nil {
+		t.Errorf("expected an error for a matrix with fewer rows than columns")
+	}
+}
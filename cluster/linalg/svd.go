@@ -0,0 +1,118 @@
+package linalg
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	matrix "github.com/skelterjohn/go.matrix"
+)
+
+const orthogonalityEpsilon = 1e-15
+
+// SVD computes the thin singular value decomposition of a matrix with at least
+// as many rows as columns using the one-sided Jacobi method, which works on
+// the matrix directly rather than on its square and so preserves the accuracy
+// of small singular values. The singular values are returned in descending
+// order with the matching left and right singular vectors as the columns of U
+// and V. The columns of V are oriented as in OrientColumns and U is flipped
+// to match
+func SVD(m *matrix.DenseMatrix) (*matrix.DenseMatrix, []float64, *matrix.DenseMatrix, error) {
+	rows, n := m.GetSize()
+	if rows < n {
+		return nil, nil, nil, errors.New("SVD requires at least as many rows as columns")
+	}
+
+	a := m.Copy().Arrays()
+	v := make([][]float64, n)
+	for i := range v {
+		v[i] = make([]float64, n)
+		v[i][i] = 1
+	}
+
+	converged := false
+	for sweep := 0; sweep < maxSweeps; sweep++ {
+		rotated := false
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				var alpha, beta, gamma float64
+				for i := 0; i < rows; i++ {
+					alpha += a[i][p] * a[i][p]
+					beta += a[i][q] * a[i][q]
+					gamma += a[i][p] * a[i][q]
+				}
+				if gamma == 0 || math.Abs(gamma) <= orthogonalityEpsilon*math.Sqrt(alpha*beta) {
+					continue
+				}
+				rotated = true
+
+				zeta := (beta - alpha) / (2 * gamma)
+				t := 1 / (math.Abs(zeta) + math.Sqrt(1+zeta*zeta))
+				if zeta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(1+t*t)
+				s := c * t
+
+				for i := 0; i < rows; i++ {
+					aip := a[i][p]
+					aiq := a[i][q]
+					a[i][p] = c*aip - s*aiq
+					a[i][q] = s*aip + c*aiq
+				}
+				for i := 0; i < n; i++ {
+					vip := v[i][p]
+					viq := v[i][q]
+					v[i][p] = c*vip - s*viq
+					v[i][q] = s*vip + c*viq
+				}
+			}
+		}
+		if !rotated {
+			converged = true
+			break
+		}
+	}
+	if !converged {
+		return nil, nil, nil, errors.New("Jacobi singular value algorithm did not converge")
+	}
+
+	order := &descending{
+		indices: make([]int, n),
+		values:  make([]float64, n),
+	}
+	for j := 0; j < n; j++ {
+		var norm float64
+		for i := 0; i < rows; i++ {
+			norm += a[i][j] * a[i][j]
+		}
+		order.indices[j] = j
+		order.values[j] = math.Sqrt(norm)
+	}
+	sort.Stable(order)
+
+	values := make([]float64, n)
+	left := matrix.Zeros(rows, n)
+	right := matrix.Zeros(n, n)
+	for k, col := range order.indices {
+		values[k] = order.values[k]
+		for i := 0; i < n; i++ {
+			right.Set(i, k, v[i][col])
+		}
+		if values[k] == 0 {
+			continue
+		}
+		for i := 0; i < rows; i++ {
+			left.Set(i, k, a[i][col]/values[k])
+		}
+	}
+
+	signs := OrientColumns(right)
+	for k, sign := range signs {
+		for i := 0; i < rows; i++ {
+			left.Set(i, k, sign*left.Get(i, k))
+		}
+	}
+
+	return left, values, right, nil
+}
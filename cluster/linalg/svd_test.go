@@ -0,0 +1,79 @@
+package linalg
+
+import (
+	"math"
+	"testing"
+
+	matrix "github.com/skelterjohn/go.matrix"
+)
+
+func TestSVD(t *testing.T) {
+	tests := []struct {
+		name   string
+		m      [][]float64
+		values []float64
+	}{
+		{
+			name:   "diagonal",
+			m:      [][]float64{{0, 2}, {3, 0}, {0, 0}},
+			values: []float64{3, 2},
+		},
+		{
+			name:   "square",
+			m:      [][]float64{{3, 0}, {4, 5}},
+			values: []float64{math.Sqrt(45), math.Sqrt(5)},
+		},
+		{
+			name:   "rank deficient",
+			m:      [][]float64{{1, 2}, {2, 4}, {3, 6}},
+			values: []float64{math.Sqrt(70), 0},
+		},
+	}
+
+	for _, test := range tests {
+		m := matrix.MakeDenseMatrixStacked(test.m)
+		u, values, v, err := SVD(m)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if !nearMatrix(m, matrix.MakeDenseMatrixStacked(test.m)) {
+			t.Errorf("%s: input matrix was modified", test.name)
+		}
+		for k := range values {
+			if !near(values[k], test.values[k]) {
+				t.Errorf("%s: singular value %d is %v, want %v", test.name, k, values[k], test.values[k])
+			}
+		}
+		if !orthonormalColumns(v) {
+			t.Errorf("%s: right singular vectors are not orthonormal", test.name)
+		}
+
+		// U diag(values) V^T reconstructs the matrix, even where U has a
+		// zero column for a zero singular value
+		scaled := u.Copy()
+		for i := 0; i < scaled.Rows(); i++ {
+			for k := range values {
+				scaled.Set(i, k, scaled.Get(i, k)*values[k])
+			}
+		}
+		product, err := scaled.TimesDense(v.Transpose())
+		if err != nil || !nearMatrix(product, m) {
+			t.Errorf("%s: singular vectors do not reconstruct the matrix", test.name)
+		}
+
+		// The right singular vectors are oriented, so orienting them again
+		// leaves every sign unchanged
+		for j, sign := range OrientColumns(v.Copy()) {
+			if sign != 1 {
+				t.Errorf("%s: right singular vector %d is not oriented", test.name, j)
+			}
+		}
+	}
+}
+
+func TestSVDWide(t *testing.T) {
+	if _, _, _, err := SVD(matrix.Zeros(2, 3)); err == nil {
+		t.Errorf("expected an error for a matrix with fewer rows than columns")
+	}
+}
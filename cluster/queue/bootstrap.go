@@ -0,0 +1,139 @@
+package queue
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+const (
+	// bootstrapSeed seeds the resampling of the first bootstrap replicate on
+	// the first worker
+	bootstrapSeed = 1
+	// confidenceLevel is the coverage of the bootstrap percentile intervals
+	confidenceLevel = 0.95
+)
+
+// bootstrap estimates confidence intervals for the eigenvalues and for the
+// loadings of the job's top components. Each replicate has every worker
+// resample its partition with replacement, the resampled moments are merged
+// into a scatter matrix, rescaled to unit variances when the job standardizes,
+// and eigen-decomposed. Replicate eigenvectors are flipped to agree in sign
+// with the original ones before their loadings are collected. The percentile
+// intervals are recorded in the response as lower and upper bounds
+func bootstrap(job *Job, eigenvectors *matrix.DenseMatrix, eigenvalues []float64, cols int, resp *Response) error {
+	k := job.Components
+	if k > len(eigenvalues) {
+		k = len(eigenvalues)
+	}
+
+	values := make([][]float64, len(eigenvalues))
+	loadings := make([][][]float64, k)
+	for c := range loadings {
+		loadings[c] = make([][]float64, cols)
+	}
+	for replicate := 0; replicate < job.BootstrapReplicates; replicate++ {
+		scatter, err := getBootstrapScatter(job, replicate, cols)
+		if err != nil {
+			return err
+		}
+
+		vectors, replicateValues, err := linalg.SymmetricEigen(scatter)
+		if err != nil {
+			grpclog.Printf("Failed to compute SymmetricEigen(): %v", err)
+			return errors.New("Could not compute eigenvalues/vectors")
+		}
+		for c := range values {
+			values[c] = append(values[c], replicateValues[c])
+		}
+		for c := range loadings {
+			var dot float64
+			for i := 0; i < cols; i++ {
+				dot += vectors.Get(i, c) * eigenvectors.Get(i, c)
+			}
+			sign := 1.0
+			if dot < 0 {
+				sign = -1
+			}
+			for i := 0; i < cols; i++ {
+				loadings[c][i] = append(loadings[c][i], sign*vectors.Get(i, c))
+			}
+		}
+	}
+
+	tail := (1 - confidenceLevel) / 2
+	resp.EigenvalueIntervals = make([][]float64, len(values))
+	for c := range values {
+		resp.EigenvalueIntervals[c] = []float64{percentile(values[c], tail), percentile(values[c], 1-tail)}
+	}
+	resp.LoadingIntervals = make([][][]float64, k)
+	for c := range loadings {
+		resp.LoadingIntervals[c] = make([][]float64, cols)
+		for i := range loadings[c] {
+			resp.LoadingIntervals[c][i] = []float64{percentile(loadings[c][i], tail), percentile(loadings[c][i], 1-tail)}
+		}
+	}
+
+	return nil
+}
+
+// getBootstrapScatter merges the moments of one bootstrap replicate from the
+// workers and returns the scatter matrix of the resampled rows
+func getBootstrapScatter(job *Job, replicate int, cols int) (*matrix.DenseMatrix, error) {
+	merged := &model{
+		mean:    make([]float64, cols),
+		scatter: matrix.Zeros(cols, cols),
+	}
+	err := execute(job, &phase{
+		name:    "GetBootstrapMoments",
+		failure: "Could not get bootstrap moments",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			resample := &pb.Resample{
+				Seed: int64(bootstrapSeed + replicate*job.Workers + i),
+			}
+			return client.GetBootstrapMoments(context.Background(), resample)
+		},
+		combine: mergeMoments(merged),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if merged.weight <= 0 {
+		return nil, errors.New("Bootstrap replicate has no weight")
+	}
+
+	scatter := merged.scatter
+	if job.Standardize {
+		sd := make([]float64, cols)
+		for i := range sd {
+			sd[i] = math.Sqrt(scatter.Get(i, i) / merged.weight)
+		}
+		for i := range sd {
+			for j := range sd {
+				if sd[i] > 0 && sd[j] > 0 {
+					scatter.Set(i, j, scatter.Get(i, j)/(sd[i]*sd[j]))
+				}
+			}
+		}
+	}
+
+	return scatter, nil
+}
+
+// percentile returns the value below which the given fraction of the values
+// lie, sorting the values in place
+func percentile(values []float64, p float64) float64 {
+	sort.Float64s(values)
+	index := int(math.Ceil(p*float64(len(values)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	return values[index]
+}
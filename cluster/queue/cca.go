@@ -0,0 +1,169 @@
+package queue
+
+import (
+	"errors"
+	"math"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+// cca loads the dataset on the workers and finds the canonical correlations
+// between the two column groups named by the job. The workers return the
+// scatter matrix of the standardized columns of both groups, whose blocks are
+// the scatter matrices Sxx and Syy of the groups and their cross scatter Sxy.
+// The canonical correlations are the singular values of
+// Sxx^-1/2 Sxy Syy^-1/2, which solves the generalized eigenproblem
+// Sxy Syy^-1 Syx a = ρ² Sxx a, and the canonical weights of each group are
+// its singular vectors mapped back through the inverse square root. The
+// weights are scaled so that every canonical variate has unit variance
+func cca(job *Job, resp *Response) error {
+	loaded = nil
+	rows, cols, weight, err := loadData(job)
+	if err != nil {
+		return err
+	}
+	resp.Rows = rows
+	resp.TotalWeight = weight
+
+	first, second, err := columnGroups(job, cols)
+	if err != nil {
+		return err
+	}
+	resp.Groups = job.Groups
+	resp.GroupColumns = [][]int{first, second}
+
+	mean, variance, err := getMoments(job, weight, cols)
+	if err != nil {
+		return err
+	}
+	sdArray := make([]float64, cols)
+	for i := range sdArray {
+		if job.Standardize {
+			sdArray[i] = math.Sqrt(variance[i] / weight)
+		} else {
+			sdArray[i] = 1
+		}
+	}
+	meanAndSD := &pb.Matrix{
+		Elements: []*pb.Vector{
+			&pb.Vector{Elements: mean},
+			&pb.Vector{Elements: sdArray},
+		},
+	}
+	err = standardize(job, meanAndSD)
+	if err != nil {
+		return err
+	}
+
+	groups := &pb.ColumnGroups{}
+	for _, j := range first {
+		groups.First = append(groups.First, int32(j))
+	}
+	for _, j := range second {
+		groups.Second = append(groups.Second, int32(j))
+	}
+	p, q := len(first), len(second)
+	scatter := matrix.Zeros(p+q, p+q)
+	err = execute(job, &phase{
+		name:    "GetGroupScatter",
+		failure: "Could not get group scatter matrix",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetGroupScatter(context.Background(), groups)
+		},
+		combine: addMatrix(scatter),
+	})
+	if err != nil {
+		return err
+	}
+
+	sxx := scatter.GetMatrix(0, 0, p, p)
+	syy := scatter.GetMatrix(p, p, q, q)
+	sxy := scatter.GetMatrix(0, p, p, q)
+	kx, err := linalg.PseudoInverseSqrt(sxx)
+	if err != nil {
+		grpclog.Printf("Failed to compute PseudoInverseSqrt(): %v", err)
+		return errors.New("Could not whiten first group")
+	}
+	ky, err := linalg.PseudoInverseSqrt(syy)
+	if err != nil {
+		grpclog.Printf("Failed to compute PseudoInverseSqrt(): %v", err)
+		return errors.New("Could not whiten second group")
+	}
+	whitened, err := kx.TimesDense(sxy)
+	if err == nil {
+		whitened, err = whitened.TimesDense(ky)
+	}
+	if err != nil {
+		grpclog.Printf("Failed to whiten cross scatter: %v", err)
+		return errors.New("Could not whiten cross scatter")
+	}
+
+	// The SVD needs at least as many rows as columns, so decompose the
+	// transpose when the second group is larger and swap the factors
+	var u, v *matrix.DenseMatrix
+	var correlations []float64
+	if p >= q {
+		u, correlations, v, err = linalg.SVD(whitened)
+	} else {
+		v, correlations, u, err = linalg.SVD(whitened.Transpose())
+	}
+	if err != nil {
+		grpclog.Printf("Failed to compute SVD(): %v", err)
+		return errors.New("Could not compute singular values/vectors")
+	}
+	for k, value := range correlations {
+		correlations[k] = math.Min(value, 1)
+	}
+	resp.CanonicalCorrelations = correlations
+
+	firstWeights, err := kx.TimesDense(u)
+	if err != nil {
+		return errors.New("Could not compute canonical weights")
+	}
+	secondWeights, err := ky.TimesDense(v)
+	if err != nil {
+		return errors.New("Could not compute canonical weights")
+	}
+	firstWeights.Scale(math.Sqrt(weight))
+	secondWeights.Scale(math.Sqrt(weight))
+	resp.CanonicalWeights = [][][]float64{
+		firstWeights.Transpose().Arrays(),
+		secondWeights.Transpose().Arrays(),
+	}
+
+	return nil
+}
+
+// columnGroups returns the feature indices of the two column groups named by
+// the job, which must be non-empty and share no columns
+func columnGroups(job *Job, cols int) ([]int, []int, error) {
+	if len(job.Groups) != 2 {
+		grpclog.Printf("Invalid column groups: %v", job.Groups)
+		return nil, nil, errors.New("Need two column groups")
+	}
+	schema := schemaFor(job.Dataset)
+	used := map[int]bool{}
+	columns := make([][]int, 2)
+	for g, name := range job.Groups {
+		group, ok := schema.Groups[name]
+		if !ok || len(group) == 0 {
+			grpclog.Printf("Unknown column group for %s: %s", job.Dataset, name)
+			return nil, nil, errors.New("Unknown column group")
+		}
+		for _, j := range group {
+			if j < 0 || j >= cols || used[j] {
+				grpclog.Printf("Invalid column %d in group %s", j, name)
+				return nil, nil, errors.New("Invalid column group")
+			}
+			used[j] = true
+		}
+		columns[g] = group
+	}
+
+	return columns[0], columns[1], nil
+}
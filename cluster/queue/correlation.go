@@ -0,0 +1,126 @@
+package queue
+
+import (
+	"errors"
+	"math"
+
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+// correlation loads the dataset on the workers and records the correlation
+// matrix of its columns, which is the scatter matrix of the standardized
+// columns divided by the total weight. A column without variance correlates
+// with nothing but itself. The columns are then clustered hierarchically so
+// that redundant columns can be shown next to each other
+func correlation(job *Job, resp *Response) error {
+	loaded = nil
+	rows, cols, weight, err := loadData(job)
+	if err != nil {
+		return err
+	}
+	resp.Rows = rows
+	resp.TotalWeight = weight
+
+	mean, variance, err := getMoments(job, weight, cols)
+	if err != nil {
+		return err
+	}
+	sdArray := make([]float64, cols)
+	for i := range sdArray {
+		sdArray[i] = math.Sqrt(variance[i] / weight)
+		if sdArray[i] == 0 {
+			sdArray[i] = 1
+		}
+	}
+	meanAndSD := &pb.Matrix{
+		Elements: []*pb.Vector{
+			&pb.Vector{Elements: mean},
+			&pb.Vector{Elements: sdArray},
+		},
+	}
+	scatter, err := getScatterMatrix(job, meanAndSD, cols)
+	if err != nil {
+		return err
+	}
+
+	scatter.Scale(1 / weight)
+	for i := 0; i < cols; i++ {
+		scatter.Set(i, i, 1)
+		for j := 0; j < cols; j++ {
+			if math.IsNaN(scatter.Get(i, j)) {
+				grpclog.Printf("Correlation of columns %d and %d is undefined", i, j)
+				return errors.New("Could not compute correlations of columns with missing values")
+			}
+		}
+	}
+	resp.Correlation = scatter.Arrays()
+	resp.FeatureOrder, resp.Dendrogram = clusterFeatures(scatter)
+
+	return nil
+}
+
+// clusterFeatures clusters the columns with average linkage on the distance
+// 1 - |r| between their correlations. It returns the columns in the order of
+// the leaves of the dendrogram along with the merges that build it, each of
+// which holds the two clusters merged, their distance and the size of the
+// merged cluster. As with SciPy's linkage, the columns are clusters 0 to n-1
+// and the cluster formed by merge m is n+m
+func clusterFeatures(correlation *matrix.DenseMatrix) ([]int, [][]float64) {
+	n := correlation.Rows()
+	if n == 0 {
+		return nil, nil
+	}
+
+	distance := make([][]float64, n)
+	ids := make([]int, n)
+	sizes := make([]float64, n)
+	leaves := make([][]int, n)
+	active := make([]bool, n)
+	for i := range distance {
+		distance[i] = make([]float64, n)
+		for j := range distance[i] {
+			distance[i][j] = 1 - math.Abs(correlation.Get(i, j))
+		}
+		ids[i] = i
+		sizes[i] = 1
+		leaves[i] = []int{i}
+		active[i] = true
+	}
+
+	var dendrogram [][]float64
+	for m := 0; m < n-1; m++ {
+		a, b := -1, -1
+		nearest := math.Inf(1)
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				if active[i] && active[j] && distance[i][j] < nearest {
+					a, b, nearest = i, j, distance[i][j]
+				}
+			}
+		}
+		dendrogram = append(dendrogram, []float64{float64(ids[a]), float64(ids[b]), nearest, sizes[a] + sizes[b]})
+
+		// The average distance to the merged cluster is the size weighted
+		// average of the distances to its two halves
+		for k := 0; k < n; k++ {
+			if active[k] && k != a && k != b {
+				distance[a][k] = (sizes[a]*distance[a][k] + sizes[b]*distance[b][k]) / (sizes[a] + sizes[b])
+				distance[k][a] = distance[a][k]
+			}
+		}
+		leaves[a] = append(leaves[a], leaves[b]...)
+		sizes[a] += sizes[b]
+		ids[a] = n + m
+		active[b] = false
+	}
+
+	for i := range active {
+		if active[i] {
+			return leaves[i], dendrogram
+		}
+	}
+	return nil, dendrogram
+}
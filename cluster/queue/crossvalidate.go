@@ -0,0 +1,112 @@
+package queue
+
+import (
+	"errors"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+// DefaultFolds is the number of folds cross-validation holds out in turn
+const DefaultFolds = 5
+
+// crossValidatePCA computes the exact principal components and the
+// cross-validated reconstruction error of keeping each number of them. For
+// each fold the workers return the moments of their rows outside the fold,
+// the coordinator merges them and eigen-decomposes the training scatter
+// matrix, and the workers report the squared error of reconstructing their
+// rows in the fold from the leading training components. The prediction
+// error sum of squares for each number of components, summed over the folds,
+// is recorded in the response. The error always falls as components are
+// added, so the curve is read for the point where it levels off
+func crossValidatePCA(job *Job, meanAndSD *pb.Matrix, rows int, weight float64, cols int, resp *Response) (*matrix.DenseMatrix, []float64, error) {
+	if job.Folds < 2 || job.Folds > rows {
+		grpclog.Printf("Invalid number of folds: %v", job.Folds)
+		return nil, nil, errors.New("Invalid number of folds")
+	}
+
+	eigenvectors, eigenvalues, err := exactPCA(job, meanAndSD, rows, weight, cols, resp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp.CrossValidation = make([]float64, cols)
+	for index := 0; index < job.Folds; index++ {
+		fold := &pb.Fold{
+			Index: int32(index),
+			Count: int32(job.Folds),
+		}
+		training, err := getTrainingModel(job, fold, cols)
+		if err != nil {
+			return nil, nil, err
+		}
+		components, _, err := linalg.SymmetricEigen(training.scatter)
+		if err != nil {
+			grpclog.Printf("Failed to compute SymmetricEigen(): %v", err)
+			return nil, nil, errors.New("Could not compute eigenvalues/vectors")
+		}
+
+		reconstruction := &pb.Reconstruction{
+			Fold:       fold,
+			Center:     &pb.Vector{Elements: training.mean},
+			Components: toProto(components.Transpose()),
+		}
+		press, err := getReconstructionError(job, reconstruction, cols)
+		if err != nil {
+			return nil, nil, err
+		}
+		for k, value := range press {
+			resp.CrossValidation[k] += value
+		}
+	}
+
+	return eigenvectors, eigenvalues, nil
+}
+
+// getTrainingModel merges the moments of the rows outside the fold from the
+// workers
+func getTrainingModel(job *Job, fold *pb.Fold, cols int) (*model, error) {
+	training := &model{
+		mean:    make([]float64, cols),
+		scatter: matrix.Zeros(cols, cols),
+	}
+	err := execute(job, &phase{
+		name:    "GetTrainingMoments",
+		failure: "Could not get training moments",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetTrainingMoments(context.Background(), fold)
+		},
+		combine: mergeMoments(training),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if training.weight <= 0 {
+		return nil, errors.New("Training folds have no weight")
+	}
+
+	return training, nil
+}
+
+// getReconstructionError sums the reconstruction errors of the rows in the
+// fold over the workers
+func getReconstructionError(job *Job, reconstruction *pb.Reconstruction, cols int) ([]float64, error) {
+	press := make([]float64, cols)
+	err := execute(job, &phase{
+		name:    "GetReconstructionError",
+		failure: "Could not get reconstruction error",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetReconstructionError(context.Background(), reconstruction)
+		},
+		combine: addVector(press),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return press, nil
+}
@@ -0,0 +1,105 @@
+package queue
+
+import (
+	"errors"
+	"math"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+// The formulations an exact job can solve its eigenproblem with
+const (
+	// FormulationScatter eigen-decomposes the cols x cols scatter matrix
+	FormulationScatter = "scatter"
+	// FormulationGram eigen-decomposes the rows x rows Gram matrix
+	FormulationGram = "gram"
+)
+
+// rankEpsilon is the fraction of the largest eigenvalue below which the
+// eigenvalues of the Gram matrix are treated as zero
+const rankEpsilon = 1e-12
+
+// dualPCA computes the principal components from the Gram matrix of inner
+// products between rows, which is smaller than the scatter matrix when there
+// are fewer rows than columns. The workers return their standardized rows,
+// the coordinator eigen-decomposes the rows x rows Gram matrix and maps each
+// eigenvector u with eigenvalue l back to the feature space as X^T u / sqrt(l).
+// Only components with non-zero eigenvalues are returned. Forming the scatter
+// matrix is what this formulation avoids, so the model it leaves cannot be
+// updated
+func dualPCA(job *Job, meanAndSD *pb.Matrix, cols int) (*matrix.DenseMatrix, []float64, error) {
+	err := standardize(job, meanAndSD)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blocks := make([]*pb.Matrix, job.Workers)
+	err = execute(job, &phase{
+		name:    "GetRowBlock",
+		failure: "Could not get row block",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetRowBlock(context.Background(), &pb.Unit{})
+		},
+		combine: collectMatrix(blocks),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := stackMatrices(blocks)
+	if err != nil {
+		return nil, nil, err
+	}
+	if data == nil {
+		return nil, nil, errors.New("No rows available")
+	}
+
+	loaded = &model{
+		dataset:     job.Dataset,
+		workers:     job.Workers,
+		formulation: FormulationGram,
+	}
+
+	gram, err := data.TimesDense(data.Transpose())
+	if err != nil {
+		grpclog.Printf("Failed to compute Gram matrix: %v", err)
+		return nil, nil, errors.New("Could not compute Gram matrix")
+	}
+
+	rowVectors, gramValues, err := linalg.SymmetricEigen(gram)
+	if err != nil {
+		grpclog.Printf("Failed to compute SymmetricEigen(): %v", err)
+		return nil, nil, errors.New("Could not compute eigenvalues/vectors")
+	}
+
+	rank := 0
+	for _, value := range gramValues {
+		if value <= rankEpsilon*gramValues[0] {
+			break
+		}
+		rank++
+	}
+	if rank < 2 {
+		grpclog.Printf("Gram matrix has rank %d", rank)
+		return nil, nil, errors.New("Not enough components")
+	}
+
+	eigenvectors, err := data.Transpose().TimesDense(rowVectors.GetMatrix(0, 0, data.Rows(), rank))
+	if err != nil {
+		grpclog.Printf("Failed to lift eigenvectors: %v", err)
+		return nil, nil, errors.New("Could not compute eigenvalues/vectors")
+	}
+	for j := 0; j < rank; j++ {
+		scale := 1 / math.Sqrt(gramValues[j])
+		for i := 0; i < cols; i++ {
+			eigenvectors.Set(i, j, scale*eigenvectors.Get(i, j))
+		}
+	}
+	linalg.OrientColumns(eigenvectors)
+
+	return eigenvectors, gramValues[:rank], nil
+}
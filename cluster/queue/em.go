@@ -0,0 +1,243 @@
+package queue
+
+import (
+	"errors"
+	"math"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+// minNoise is the smallest noise variance the EM iterations may reach, which
+// keeps the posterior of the latent variables well defined
+const minNoise = 1e-12
+
+// emPCA fits a probabilistic PCA model with the EM algorithm so that rows
+// with missing values can be used without imputing them. Each column is
+// standardized with the moments of its observed entries. Each pass sends the
+// current loadings and noise variance to the workers, which compute the
+// expected latent statistics of their rows from the observed entries, and the
+// coordinator solves for the new loadings and noise variance column by column.
+// Iteration stops once the log-likelihood changes by less than the job's
+// tolerance relative to its magnitude or the iteration limit is reached. The
+// principal axes are the left singular vectors of the loadings and the
+// eigenvalues are the squared singular values plus the noise variance, scaled
+// to the total weight. The number of passes, the noise variance and the
+// log-likelihood of the returned model are recorded in the response
+func emPCA(job *Job, weight float64, cols int, resp *Response) (*matrix.DenseMatrix, []float64, float64, error) {
+	k := job.Components
+	if k < 2 || k > cols {
+		grpclog.Printf("Invalid number of components: %v", k)
+		return nil, nil, 0, errors.New("Invalid number of components")
+	}
+	if job.MaxIterations < 1 {
+		grpclog.Printf("Invalid number of iterations: %v", job.MaxIterations)
+		return nil, nil, 0, errors.New("Invalid number of iterations")
+	}
+
+	mean, variance, err := getObservedMoments(job, cols)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	sdArray := make([]float64, cols)
+	var totalVariance float64
+	for i := range sdArray {
+		if job.Standardize {
+			sdArray[i] = math.Sqrt(variance[i])
+		} else {
+			sdArray[i] = 1
+		}
+		totalVariance += weight * variance[i] / (sdArray[i] * sdArray[i])
+	}
+	meanAndSD := &pb.Matrix{
+		Elements: []*pb.Vector{
+			&pb.Vector{Elements: mean},
+			&pb.Vector{Elements: sdArray},
+		},
+	}
+	err = standardize(job, meanAndSD)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	noise := totalVariance / weight / float64(cols)
+	loadings := randomBasis(cols, k)
+	loadings.Scale(math.Sqrt(noise))
+	var logLikelihood float64
+	for iter := 1; iter <= job.MaxIterations; iter++ {
+		resp.Iterations = iter
+
+		model := &pb.LatentModel{
+			Loadings: toProto(loadings),
+			Noise:    noise,
+		}
+		statistics, err := getLatentStatistics(job, model, cols, k)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if statistics.Observed <= 0 {
+			return nil, nil, 0, errors.New("No observed values")
+		}
+
+		previous := logLikelihood
+		logLikelihood = statistics.LogLikelihood
+		if iter > 1 && math.Abs(logLikelihood-previous) < job.Tolerance*math.Abs(previous) {
+			break
+		}
+		if iter == job.MaxIterations {
+			break
+		}
+
+		loadings, noise, err = maximizeLikelihood(statistics, cols, k)
+		if err != nil {
+			grpclog.Printf("Failed to update latent model: %v", err)
+			return nil, nil, 0, errors.New("Could not update latent model")
+		}
+	}
+	resp.NoiseVariance = noise
+	resp.LogLikelihood = logLikelihood
+
+	axes, singularValues, _, err := linalg.SVD(loadings)
+	if err != nil {
+		grpclog.Printf("Failed to compute SVD(): %v", err)
+		return nil, nil, 0, errors.New("Could not compute singular values/vectors")
+	}
+	linalg.OrientColumns(axes)
+
+	eigenvalues := make([]float64, len(singularValues))
+	for i, value := range singularValues {
+		eigenvalues[i] = weight * (value*value + noise)
+	}
+
+	return axes, eigenvalues, totalVariance, nil
+}
+
+// getObservedMoments merges the per-column moments of the observed entries
+// of each partition and returns the weighted mean and variance of each column
+func getObservedMoments(job *Job, cols int) ([]float64, []float64, error) {
+	weight := make([]float64, cols)
+	mean := make([]float64, cols)
+	squares := make([]float64, cols)
+	variance := make([]float64, cols)
+	err := execute(job, &phase{
+		name:    "GetObservedMoments",
+		failure: "Could not get observed moments",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetObservedMoments(context.Background(), &pb.Unit{})
+		},
+		combine: func(i int, result interface{}) error {
+			moments := result.(*pb.Matrix).Elements
+			if len(moments) != 3 || len(moments[0].Elements) != cols {
+				return errors.New("Inconsistent vectors sizes")
+			}
+
+			// Merge each column pairwise as in the update of Chan et al
+			for j := range mean {
+				w := moments[0].Elements[j]
+				if w <= 0 {
+					continue
+				}
+				total := weight[j] + w
+				delta := moments[1].Elements[j] - mean[j]
+				squares[j] += moments[2].Elements[j] + delta*delta*weight[j]*w/total
+				mean[j] += delta * w / total
+				weight[j] = total
+			}
+			return nil
+		},
+		reduce: func() error {
+			for j := range variance {
+				if weight[j] <= 0 {
+					grpclog.Printf("Column %d has no observed values", j)
+					return errors.New("Column has no observed values")
+				}
+				variance[j] = squares[j] / weight[j]
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return mean, variance, nil
+}
+
+// getLatentStatistics runs the expectation step on the workers and sums their
+// statistics
+func getLatentStatistics(job *Job, model *pb.LatentModel, cols int, k int) (*pb.LatentStatistics, error) {
+	products := matrix.Zeros(cols, k)
+	moments := matrix.Zeros(cols, k*k)
+	sum := &pb.LatentStatistics{}
+	err := execute(job, &phase{
+		name:    "GetLatentStatistics",
+		failure: "Could not get latent statistics",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetLatentStatistics(context.Background(), model)
+		},
+		combine: func(i int, result interface{}) error {
+			statistics := result.(*pb.LatentStatistics)
+			err := addMatrix(products)(i, statistics.Products)
+			if err != nil {
+				return err
+			}
+			err = addMatrix(moments)(i, statistics.Moments)
+			if err != nil {
+				return err
+			}
+			sum.Squares += statistics.Squares
+			sum.Observed += statistics.Observed
+			sum.LogLikelihood += statistics.LogLikelihood
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	sum.Products = toProto(products)
+	sum.Moments = toProto(moments)
+
+	return sum, nil
+}
+
+// maximizeLikelihood runs the maximization step. Row j of the new loadings is
+// A_j^-1 b_j, where b_j and A_j are the sums of y_j E[z] and E[zz^T] over the
+// rows observing column j, and the noise variance is the mean squared
+// residual of the observed entries under the new loadings
+func maximizeLikelihood(statistics *pb.LatentStatistics, cols int, k int) (*matrix.DenseMatrix, float64, error) {
+	products := toDense(statistics.Products)
+	moments := toDense(statistics.Moments)
+
+	loadings := matrix.Zeros(cols, k)
+	residual := statistics.Squares
+	for j := 0; j < cols; j++ {
+		a := matrix.Zeros(k, k)
+		for p := 0; p < k; p++ {
+			for q := 0; q < k; q++ {
+				a.Set(p, q, moments.Get(j, p*k+q))
+			}
+		}
+		inverse, err := linalg.PseudoInverse(a)
+		if err != nil {
+			return nil, 0, err
+		}
+		row, err := inverse.TimesDense(products.GetRowVector(j).Transpose())
+		if err != nil {
+			return nil, 0, err
+		}
+		for p := 0; p < k; p++ {
+			loadings.Set(j, p, row.Get(p, 0))
+			residual -= row.Get(p, 0) * products.Get(j, p)
+		}
+	}
+
+	noise := residual / statistics.Observed
+	if noise < minNoise {
+		noise = minNoise
+	}
+	return loadings, noise, nil
+}
@@ -0,0 +1,57 @@
+package queue
+
+import (
+	"math"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+// fakeWorker answers the phases of a job with the fixed scatter matrix of a
+// standardized partition of the given weight whose mean is zero. The methods
+// it does not override panic through the nil embedded client
+type fakeWorker struct {
+	pb.WorkerClient
+	weight  float64
+	scatter *matrix.DenseMatrix
+}
+
+// useFakeWorkers replaces the worker clients with the fakes
+func useFakeWorkers(workers ...*fakeWorker) {
+	clients = make([]pb.WorkerClient, len(workers))
+	for i, w := range workers {
+		clients[i] = w
+	}
+}
+
+func (w *fakeWorker) Standardize(ctx context.Context, in *pb.Matrix, opts ...grpc.CallOption) (*pb.Unit, error) {
+	return &pb.Unit{}, nil
+}
+
+func (w *fakeWorker) GetScatterMatrix(ctx context.Context, in *pb.Matrix, opts ...grpc.CallOption) (*pb.Matrix, error) {
+	return toProto(w.scatter), nil
+}
+
+func (w *fakeWorker) GetRobustMoments(ctx context.Context, in *pb.RobustEstimate, opts ...grpc.CallOption) (*pb.Moments, error) {
+	return &pb.Moments{
+		Sum:     &pb.Vector{Elements: make([]float64, w.scatter.Cols())},
+		Scatter: toProto(w.scatter),
+		Weight:  w.weight,
+	}, nil
+}
+
+func (w *fakeWorker) GetRobustWeights(ctx context.Context, in *pb.Unit, opts ...grpc.CallOption) (*pb.Vector, error) {
+	return &pb.Vector{}, nil
+}
+
+// sameDirection returns whether two unit columns are equal up to sign
+func sameDirection(a, b *matrix.DenseMatrix) bool {
+	var dot float64
+	for i := 0; i < a.Rows(); i++ {
+		dot += a.Get(i, 0) * b.Get(i, 0)
+	}
+	return math.Abs(math.Abs(dot)-1) < 1e-6
+}
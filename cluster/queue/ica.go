@@ -0,0 +1,168 @@
+package queue
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+// icaSeed seeds the initial unmixing matrix of FastICA
+const icaSeed = 1
+
+// ica separates the job's number of independent components from the principal
+// components already computed for the job. The top components, scaled to
+// unit variance, whiten the standardized rows, and symmetric FastICA with the
+// log cosh contrast then looks for the rotation of the whitened rows whose
+// projections are least Gaussian. Each pass sends the whitening and the
+// current unmixing matrix W to the workers, which return the expectations of
+// tanh(Wz) z^T and of its derivative, and the coordinator takes the update
+// E[g(Wz) z^T] - diag(E[g'(Wz)]) W followed by the symmetric decorrelation
+// (W W^T)^-1/2 W. Iteration stops once no row of W turns by more than the
+// job's tolerance or the iteration limit is reached. The unmixing matrices in
+// the whitened and the standardized space and the scores of every row on the
+// independent components are recorded in the response
+func ica(job *Job, eigenvectors *matrix.DenseMatrix, eigenvalues []float64, resp *Response) error {
+	k := job.Components
+	cols := eigenvectors.Rows()
+	if k < 2 || k > eigenvectors.Cols() {
+		grpclog.Printf("Invalid number of components: %v", k)
+		return errors.New("Invalid number of components")
+	}
+
+	whitening := matrix.Zeros(k, cols)
+	for c := 0; c < k; c++ {
+		if eigenvalues[c] <= 0 {
+			grpclog.Printf("Component %d has eigenvalue %v", c, eigenvalues[c])
+			return errors.New("Cannot whiten components without variance")
+		}
+		scale := math.Sqrt(resp.TotalWeight / eigenvalues[c])
+		for j := 0; j < cols; j++ {
+			whitening.Set(c, j, scale*eigenvectors.Get(j, c))
+		}
+	}
+
+	r := rand.New(rand.NewSource(icaSeed))
+	unmixing := matrix.Zeros(k, k)
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			unmixing.Set(i, j, r.NormFloat64())
+		}
+	}
+	unmixing, err := decorrelate(unmixing)
+	if err != nil {
+		return err
+	}
+
+	for iter := 1; iter <= job.MaxIterations; iter++ {
+		resp.IndependentIterations = iter
+
+		model := &pb.IndependentModel{
+			Whitening: toProto(whitening),
+			Unmixing:  toProto(unmixing),
+		}
+		statistics, err := getIndependentStatistics(job, model, k)
+		if err != nil {
+			return err
+		}
+
+		updated := toDense(statistics.Products)
+		for c := 0; c < k; c++ {
+			for j := 0; j < k; j++ {
+				value := updated.Get(c, j) - statistics.Derivatives.Elements[c]*unmixing.Get(c, j)
+				updated.Set(c, j, value/statistics.Weight)
+			}
+		}
+		updated, err = decorrelate(updated)
+		if err != nil {
+			return err
+		}
+
+		var change float64
+		for c := 0; c < k; c++ {
+			var dot float64
+			for j := 0; j < k; j++ {
+				dot += updated.Get(c, j) * unmixing.Get(c, j)
+			}
+			change = math.Max(change, 1-math.Abs(dot))
+		}
+		unmixing = updated
+		if change < job.Tolerance {
+			break
+		}
+	}
+
+	full, err := unmixing.TimesDense(whitening)
+	if err != nil {
+		grpclog.Printf("Failed to compose unmixing matrix: %v", err)
+		return errors.New("Could not compute unmixing matrix")
+	}
+	transposed := full.Transpose()
+	signs := linalg.OrientColumns(transposed)
+	full = transposed.Transpose()
+	for c, sign := range signs {
+		for j := 0; j < k; j++ {
+			unmixing.Set(c, j, sign*unmixing.Get(c, j))
+		}
+	}
+	resp.WhiteningMatrix = whitening.Arrays()
+	resp.WhitenedUnmixing = unmixing.Arrays()
+	resp.UnmixingMatrix = full.Arrays()
+
+	resp.Scores, err = getLabeledScores(job, toProto(full))
+	return err
+}
+
+// decorrelate returns (W W^T)^-1/2 W, the matrix with orthonormal rows
+// nearest to W
+func decorrelate(unmixing *matrix.DenseMatrix) (*matrix.DenseMatrix, error) {
+	gram, err := unmixing.TimesDense(unmixing.Transpose())
+	if err != nil {
+		return nil, err
+	}
+	inverseSqrt, err := linalg.PseudoInverseSqrt(gram)
+	if err != nil {
+		grpclog.Printf("Failed to compute PseudoInverseSqrt(): %v", err)
+		return nil, errors.New("Could not decorrelate unmixing matrix")
+	}
+	return inverseSqrt.TimesDense(unmixing)
+}
+
+// getIndependentStatistics sums the FastICA expectations over the workers
+func getIndependentStatistics(job *Job, model *pb.IndependentModel, k int) (*pb.IndependentStatistics, error) {
+	products := matrix.Zeros(k, k)
+	sum := &pb.IndependentStatistics{
+		Derivatives: &pb.Vector{Elements: make([]float64, k)},
+	}
+	err := execute(job, &phase{
+		name:    "GetIndependentStatistics",
+		failure: "Could not get independent statistics",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetIndependentStatistics(context.Background(), model)
+		},
+		combine: func(i int, result interface{}) error {
+			statistics := result.(*pb.IndependentStatistics)
+			err := addMatrix(products)(i, statistics.Products)
+			if err != nil {
+				return err
+			}
+			sum.Weight += statistics.Weight
+			return addVector(sum.Derivatives.Elements)(i, statistics.Derivatives)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if sum.Weight <= 0 {
+		return nil, errors.New("Dataset has no weight")
+	}
+	sum.Products = toProto(products)
+
+	return sum, nil
+}
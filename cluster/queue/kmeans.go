@@ -0,0 +1,272 @@
+package queue
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+// The spaces a job can cluster its rows in
+const (
+	// SpaceRaw clusters the standardized rows
+	SpaceRaw = "raw"
+	// SpaceScores clusters the projections of the rows onto the top two
+	// principal components
+	SpaceScores = "scores"
+)
+
+const (
+	// kmeansSeed seeds the sampling of the first k-means|| round on the first
+	// worker and the k-means++ reclustering of the candidates
+	kmeansSeed = 1
+	// kmeansRounds is the number of k-means|| sampling rounds
+	kmeansRounds = 5
+)
+
+var spaces = map[string]bool{
+	SpaceRaw:    true,
+	SpaceScores: true,
+}
+
+// ValidSpace returns whether a job can cluster its rows in the given space
+func ValidSpace(space string) bool {
+	return spaces[space]
+}
+
+// kmeans clusters the rows held by the workers into the job's number of
+// clusters. Centroids are initialized with k-means||: each round the workers
+// sample about 2k rows in proportion to their squared distance from the
+// current candidates, and the weighted candidates are then reclustered into k
+// centroids with k-means++ on the coordinator. Lloyd iterations follow, with
+// the workers returning the weighted sum and weight of the rows nearest each
+// centroid, until no centroid moves further than the job's tolerance or the
+// iteration limit is reached. The workers keep the final assignments for the
+// exported scores. The centroids projected onto the top two components, the
+// weight of each cluster and the within-cluster sum of squares are recorded
+// in the response
+func kmeans(job *Job, top *pb.Matrix, resp *Response) error {
+	k := job.Clusters
+	projection := &pb.Matrix{}
+	if job.ClusterSpace == SpaceScores {
+		projection = top
+	}
+
+	candidates := &pb.Matrix{}
+	cost := resp.TotalWeight
+	var sums *pb.ClusterSums
+	var err error
+	for round := 0; round < kmeansRounds && cost > 0; round++ {
+		samples := make([]*pb.Matrix, job.Workers)
+		err = execute(job, &phase{
+			name:    "SampleCentroids",
+			failure: "Could not sample centroids",
+			task: func(i int, client pb.WorkerClient) (interface{}, error) {
+				clustering := &pb.Clustering{
+					Centroids:  candidates,
+					Projection: projection,
+					Factor:     2 * float64(k) / cost,
+					Seed:       int64(kmeansSeed + round*job.Workers + i),
+				}
+				return client.SampleCentroids(context.Background(), clustering)
+			},
+			combine: collectMatrix(samples),
+		})
+		if err != nil {
+			return err
+		}
+		// The samples are taken in worker order so that the seeded reclustering
+		// sees the same candidates on every run
+		sampled := &pb.Matrix{}
+		for _, sample := range samples {
+			sampled.Elements = append(sampled.Elements, sample.Elements...)
+		}
+		if len(sampled.Elements) == 0 {
+			continue
+		}
+		candidates = &pb.Matrix{
+			Elements: append(append([]*pb.Vector{}, candidates.Elements...), sampled.Elements...),
+		}
+
+		sums, err = assignClusters(job, candidates, projection)
+		if err != nil {
+			return err
+		}
+		cost = sums.Cost
+	}
+	if len(candidates.Elements) < k {
+		grpclog.Printf("Sampled %d candidates for %d clusters", len(candidates.Elements), k)
+		return errors.New("Not enough rows to cluster")
+	}
+
+	centroids := recluster(toDense(candidates), sums.Weights.Elements, k, job)
+	for iter := 1; iter <= job.MaxIterations; iter++ {
+		resp.ClusterIterations = iter
+		sums, err = assignClusters(job, toProto(centroids), projection)
+		if err != nil {
+			return err
+		}
+
+		var moved float64
+		for c := 0; c < k; c++ {
+			weight := sums.Weights.Elements[c]
+			if weight <= 0 {
+				continue
+			}
+			var distance float64
+			for j := 0; j < centroids.Cols(); j++ {
+				value := sums.Sums.Elements[c].Elements[j] / weight
+				distance += (value - centroids.Get(c, j)) * (value - centroids.Get(c, j))
+				centroids.Set(c, j, value)
+			}
+			moved = math.Max(moved, math.Sqrt(distance))
+		}
+		if moved < job.Tolerance {
+			break
+		}
+	}
+
+	sums, err = assignClusters(job, toProto(centroids), projection)
+	if err != nil {
+		return err
+	}
+	resp.ClusterSizes = sums.Weights.Elements
+	resp.ClusterCost = sums.Cost
+
+	if job.ClusterSpace != SpaceScores {
+		centroids, err = toDense(top).TimesDense(centroids.Transpose())
+		if err != nil {
+			grpclog.Printf("Failed to project centroids: %v", err)
+			return errors.New("Could not project centroids")
+		}
+		centroids = centroids.Transpose()
+	}
+	resp.Centroids = centroids.Arrays()
+
+	return nil
+}
+
+// assignClusters has the workers assign their rows to the nearest centroids
+// and sums the weighted rows, weights and squared distances of each cluster
+func assignClusters(job *Job, centroids *pb.Matrix, projection *pb.Matrix) (*pb.ClusterSums, error) {
+	k := len(centroids.Elements)
+	sums := matrix.Zeros(k, len(centroids.Elements[0].Elements))
+	total := &pb.ClusterSums{
+		Weights: &pb.Vector{Elements: make([]float64, k)},
+	}
+	clustering := &pb.Clustering{
+		Centroids:  centroids,
+		Projection: projection,
+	}
+	err := execute(job, &phase{
+		name:    "AssignClusters",
+		failure: "Could not assign clusters",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.AssignClusters(context.Background(), clustering)
+		},
+		combine: func(i int, result interface{}) error {
+			clusterSums := result.(*pb.ClusterSums)
+			err := addVector(total.Weights.Elements)(i, clusterSums.Weights)
+			if err != nil {
+				return err
+			}
+			total.Cost += clusterSums.Cost
+			return addMatrix(sums)(i, clusterSums.Sums)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	total.Sums = toProto(sums)
+
+	return total, nil
+}
+
+// recluster reduces the weighted candidates to k centroids, seeding them with
+// k-means++ and refining them with Lloyd iterations on the coordinator
+func recluster(candidates *matrix.DenseMatrix, weights []float64, k int, job *Job) *matrix.DenseMatrix {
+	n, cols := candidates.GetSize()
+	r := rand.New(rand.NewSource(kmeansSeed))
+
+	centroids := matrix.Zeros(k, cols)
+	distances := make([]float64, n)
+	for i := range distances {
+		distances[i] = 1
+	}
+	for c := 0; c < k; c++ {
+		var total float64
+		for i := range distances {
+			total += weights[i] * distances[i]
+		}
+		chosen := n - 1
+		target := r.Float64() * total
+		for i := range distances {
+			target -= weights[i] * distances[i]
+			if target < 0 {
+				chosen = i
+				break
+			}
+		}
+		for j := 0; j < cols; j++ {
+			centroids.Set(c, j, candidates.Get(chosen, j))
+		}
+		for i := range distances {
+			_, distance := nearest(candidates, i, centroids.GetMatrix(0, 0, c+1, cols))
+			distances[i] = distance
+		}
+	}
+
+	for iter := 0; iter < job.MaxIterations; iter++ {
+		sums := matrix.Zeros(k, cols)
+		clusterWeights := make([]float64, k)
+		for i := 0; i < n; i++ {
+			c, _ := nearest(candidates, i, centroids)
+			clusterWeights[c] += weights[i]
+			for j := 0; j < cols; j++ {
+				sums.Set(c, j, sums.Get(c, j)+weights[i]*candidates.Get(i, j))
+			}
+		}
+		changed := false
+		for c := 0; c < k; c++ {
+			if clusterWeights[c] <= 0 {
+				continue
+			}
+			for j := 0; j < cols; j++ {
+				value := sums.Get(c, j) / clusterWeights[c]
+				if value != centroids.Get(c, j) {
+					changed = true
+				}
+				centroids.Set(c, j, value)
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	return centroids
+}
+
+// nearest returns the index of the centroid nearest to row i of the points
+// along with the squared distance between them
+func nearest(points *matrix.DenseMatrix, i int, centroids *matrix.DenseMatrix) (int, float64) {
+	index := 0
+	best := math.Inf(1)
+	for c := 0; c < centroids.Rows(); c++ {
+		var distance float64
+		for j := 0; j < points.Cols(); j++ {
+			d := points.Get(i, j) - centroids.Get(c, j)
+			distance += d * d
+		}
+		if distance < best {
+			index = c
+			best = distance
+		}
+	}
+	return index, best
+}
@@ -0,0 +1,251 @@
+package queue
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+// lda loads the dataset on the workers and finds the linear discriminants of
+// the classes in its answers file. The workers return the weight, sum and
+// within-class scatter matrix of each class in their standardized partition,
+// which the coordinator merges per class. The discriminant directions solve
+// the generalized eigenproblem Sb v = λ Sw v for the between-class scatter Sb
+// and the pooled within-class scatter Sw, found by whitening Sb with Sw^-1/2.
+// At most one fewer direction than there are classes has a non-zero
+// eigenvalue, so only those directions are recorded in the response, as unit
+// eigenvectors, along with the class means and the scores of every row on the
+// top two directions
+func lda(job *Job, resp *Response) error {
+	loaded = nil
+	rows, cols, weight, err := loadData(job)
+	if err != nil {
+		return err
+	}
+	resp.Rows = rows
+	resp.TotalWeight = weight
+	if cols < 2 {
+		return errors.New("Not enough columns for two discriminants")
+	}
+
+	mean, variance, err := getMoments(job, weight, cols)
+	if err != nil {
+		return err
+	}
+	sdArray := make([]float64, cols)
+	for i := range sdArray {
+		if job.Standardize {
+			sdArray[i] = math.Sqrt(variance[i] / weight)
+		} else {
+			sdArray[i] = 1
+		}
+	}
+	meanAndSD := &pb.Matrix{
+		Elements: []*pb.Vector{
+			&pb.Vector{Elements: mean},
+			&pb.Vector{Elements: sdArray},
+		},
+	}
+	err = standardize(job, meanAndSD)
+	if err != nil {
+		return err
+	}
+
+	labels, classes, err := getClassModels(job, cols)
+	if err != nil {
+		return err
+	}
+	if len(labels) < 2 {
+		grpclog.Printf("Found %d classes", len(labels))
+		return errors.New("Not enough classes for discriminant analysis")
+	}
+
+	within := matrix.Zeros(cols, cols)
+	center := make([]float64, cols)
+	for _, class := range classes {
+		err = within.Add(class.scatter)
+		if err != nil {
+			return errors.New("Failed to add matrices")
+		}
+		for j := range center {
+			center[j] += class.weight * class.mean[j] / weight
+		}
+	}
+	between := matrix.Zeros(cols, cols)
+	for _, class := range classes {
+		for i := 0; i < cols; i++ {
+			for j := 0; j < cols; j++ {
+				delta := class.weight * (class.mean[i] - center[i]) * (class.mean[j] - center[j])
+				between.Set(i, j, between.Get(i, j)+delta)
+			}
+		}
+	}
+
+	whitening, err := linalg.PseudoInverseSqrt(within)
+	if err != nil {
+		grpclog.Printf("Failed to compute PseudoInverseSqrt(): %v", err)
+		return errors.New("Could not whiten within-class scatter")
+	}
+	whitened, err := whitening.TimesDense(between)
+	if err == nil {
+		whitened, err = whitened.TimesDense(whitening)
+	}
+	if err != nil {
+		grpclog.Printf("Failed to whiten between-class scatter: %v", err)
+		return errors.New("Could not whiten between-class scatter")
+	}
+	vectors, eigenvalues, err := linalg.SymmetricEigen(whitened)
+	if err != nil {
+		grpclog.Printf("Failed to compute SymmetricEigen(): %v", err)
+		return errors.New("Could not compute eigenvalues/vectors")
+	}
+	directions, err := whitening.TimesDense(vectors)
+	if err != nil {
+		grpclog.Printf("Failed to map discriminants: %v", err)
+		return errors.New("Could not compute discriminants")
+	}
+	for k := 0; k < cols; k++ {
+		var norm float64
+		for i := 0; i < cols; i++ {
+			norm += directions.Get(i, k) * directions.Get(i, k)
+		}
+		norm = math.Sqrt(norm)
+		for i := 0; norm > 0 && i < cols; i++ {
+			directions.Set(i, k, directions.Get(i, k)/norm)
+		}
+	}
+	linalg.OrientColumns(directions)
+
+	// the between-class scatter has rank at most one less than the number of
+	// classes, so only that many discriminants separate the classes
+	discriminants := len(labels) - 1
+	if discriminants > cols {
+		discriminants = cols
+	}
+	resp.Eigenvalues = eigenvalues[:discriminants]
+	resp.Eigenvectors = directions.GetMatrix(0, 0, cols, discriminants).Transpose().Arrays()
+	var separation float64
+	for _, value := range resp.Eigenvalues {
+		separation += value
+	}
+	plotted := resp.Eigenvalues[0]
+	if discriminants > 1 {
+		plotted += resp.Eigenvalues[1]
+	}
+	if separation > 0 {
+		resp.PercentVariance = 100 * plotted / separation
+	}
+
+	// two classes have a single discriminant, which is plotted against a zero
+	// second axis
+	second := make([]float64, cols)
+	if discriminants > 1 {
+		second = resp.Eigenvectors[1]
+	}
+	top := &pb.Matrix{
+		Elements: []*pb.Vector{
+			&pb.Vector{Elements: resp.Eigenvectors[0]},
+			&pb.Vector{Elements: second},
+		},
+	}
+	resp.Classes = labels
+	resp.ClassSizes = make([]float64, len(classes))
+	resp.ClassMeans = make([][]float64, len(classes))
+	for c, class := range classes {
+		resp.ClassSizes[c] = class.weight
+		resp.ClassMeans[c] = make([]float64, 2)
+		for k := range resp.ClassMeans[c] {
+			for j := 0; j < cols; j++ {
+				resp.ClassMeans[c][k] += top.Elements[k].Elements[j] * class.mean[j]
+			}
+		}
+	}
+
+	resp.Scores, err = getLabeledScores(job, top)
+	return err
+}
+
+// getClassModels merges the moments of each class from the workers and
+// returns the class labels in ascending order along with their models
+func getClassModels(job *Job, cols int) ([]float64, []*model, error) {
+	merged := map[float64]*model{}
+	err := execute(job, &phase{
+		name:    "GetClassMoments",
+		failure: "Could not get class moments",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetClassMoments(context.Background(), &pb.Unit{})
+		},
+		combine: func(i int, result interface{}) error {
+			classes := result.(*pb.ClassMoments)
+			if len(classes.Labels) != len(classes.Moments) {
+				return errors.New("Inconsistent class sizes")
+			}
+			for c, label := range classes.Labels {
+				if merged[label] == nil {
+					merged[label] = &model{
+						mean:    make([]float64, cols),
+						scatter: matrix.Zeros(cols, cols),
+					}
+				}
+				err := mergeMoments(merged[label])(i, classes.Moments[c])
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	labels := []float64{}
+	for label, class := range merged {
+		if class.weight > 0 {
+			labels = append(labels, label)
+		}
+	}
+	sort.Float64s(labels)
+	classes := make([]*model, len(labels))
+	for c, label := range labels {
+		classes[c] = merged[label]
+	}
+
+	return labels, classes, nil
+}
+
+// getLabeledScores projects the standardized rows of every worker onto the
+// directions and returns the scores with the class of each row appended, in
+// worker order
+func getLabeledScores(job *Job, directions *pb.Matrix) ([][]float64, error) {
+	blocks := make([][]*pb.Vector, job.Workers)
+	err := execute(job, &phase{
+		name:    "GetLabeledScores",
+		failure: "Could not get labeled scores",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetLabeledScores(context.Background(), directions)
+		},
+		combine: func(i int, result interface{}) error {
+			blocks[i] = result.(*pb.Matrix).Elements
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	scores := [][]float64{}
+	for _, block := range blocks {
+		for _, vector := range block {
+			scores = append(scores, vector.Elements)
+		}
+	}
+	return scores, nil
+}
@@ -0,0 +1,195 @@
+package queue
+
+import (
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+// mapTask runs one RPC against the partition held by worker i and returns its
+// result
+type mapTask func(i int, client pb.WorkerClient) (interface{}, error)
+
+// combiner folds the result of worker i's map task into the state of a phase.
+// Results are combined one at a time in the order the workers finish, so a
+// combiner that needs worker order must use the index. The error a combiner
+// returns is passed on to the user as it is
+type combiner func(i int, result interface{}) error
+
+// phase is one round trip to the workers: a map task run on every partition
+// in parallel, a combiner folding each result into the phase's state as it
+// arrives and an optional reducer run once every result is combined
+type phase struct {
+	name    string // name of the RPC, used in logs and timings
+	failure string // message of the error returned if a map task fails
+	task    mapTask
+	combine combiner
+	reduce  func() error
+}
+
+// Timing is the number of times a phase ran during a job and the total time
+// spent in it
+type Timing struct {
+	Name    string  `json:"name"`
+	Calls   int     `json:"calls"`
+	Elapsed float64 `json:"elapsed"`
+}
+
+// execute runs the phases one after another on the job's workers, stopping
+// at the first one that fails. Every result of a failed phase is still
+// received so that no map task is left blocked. The time spent in each phase
+// is added to the job's timings
+func execute(job *Job, phases ...*phase) error {
+	for _, p := range phases {
+		err := p.run(job)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type taskResponse struct {
+	Index  int
+	Result interface{}
+	Error  error
+}
+
+func (p *phase) run(job *Job) error {
+	startTime := time.Now()
+	defer func() {
+		job.record(p.name, time.Now().Sub(startTime))
+	}()
+
+	resultc := make(chan taskResponse)
+	for i := 0; i < job.Workers; i++ {
+		go func(i int, client pb.WorkerClient) {
+			result, err := p.task(i, client)
+			resultc <- taskResponse{
+				Index:  i,
+				Result: result,
+				Error:  err,
+			}
+		}(i, clients[i])
+	}
+	var failed error
+	for i := 0; i < job.Workers; i++ {
+		taskResp := <-resultc
+		if failed != nil {
+			continue
+		}
+		if taskResp.Error != nil {
+			grpclog.Printf("%v.%s() got error %v", clients[taskResp.Index], p.name, taskResp.Error)
+			failed = errors.New(p.failure)
+			continue
+		}
+		if p.combine == nil {
+			continue
+		}
+		err := p.combine(taskResp.Index, taskResp.Result)
+		if err != nil {
+			grpclog.Printf("Failed to combine %s() result: %v", p.name, err)
+			failed = err
+		}
+	}
+	if failed != nil || p.reduce == nil {
+		return failed
+	}
+
+	return p.reduce()
+}
+
+// record adds the time spent in one run of the named phase to the job's
+// timings
+func (job *Job) record(name string, elapsed time.Duration) {
+	for _, timing := range job.timings {
+		if timing.Name == name {
+			timing.Calls++
+			timing.Elapsed += elapsed.Seconds()
+			return
+		}
+	}
+	job.timings = append(job.timings, &Timing{
+		Name:    name,
+		Calls:   1,
+		Elapsed: elapsed.Seconds(),
+	})
+}
+
+// addMatrix returns a combiner adding matrix results into the sum
+func addMatrix(sum *matrix.DenseMatrix) combiner {
+	return func(i int, result interface{}) error {
+		err := sum.Add(toDense(result.(*pb.Matrix)))
+		if err != nil {
+			return errors.New("Failed to add matrices")
+		}
+		return nil
+	}
+}
+
+// addVector returns a combiner adding vector results into the sum
+func addVector(sum []float64) combiner {
+	return func(i int, result interface{}) error {
+		vector := result.(*pb.Vector)
+		if len(vector.Elements) != len(sum) {
+			return errors.New("Inconsistent vectors sizes")
+		}
+		for j, x := range vector.Elements {
+			sum[j] += x
+		}
+		return nil
+	}
+}
+
+// mergeMoments returns a combiner merging moment results into the model.
+// Results without weight are skipped
+func mergeMoments(m *model) combiner {
+	return func(i int, result interface{}) error {
+		moments := result.(*pb.Moments)
+		cols := len(m.mean)
+		if len(moments.Sum.Elements) != cols || len(moments.Scatter.Elements) != cols {
+			return errors.New("Inconsistent vectors sizes")
+		}
+		if moments.Weight <= 0 {
+			return nil
+		}
+		err := m.merge(moments)
+		if err != nil {
+			return errors.New("Could not merge moments")
+		}
+		return nil
+	}
+}
+
+// collectMatrix returns a combiner keeping the matrix result of worker i at
+// index i of the blocks, so that results can be used in worker order whatever
+// order they arrive in
+func collectMatrix(blocks []*pb.Matrix) combiner {
+	return func(i int, result interface{}) error {
+		blocks[i] = result.(*pb.Matrix)
+		return nil
+	}
+}
+
+// stackMatrices stacks the rows of the blocks in order. It returns nil if no
+// block has rows
+func stackMatrices(blocks []*pb.Matrix) (*matrix.DenseMatrix, error) {
+	rows := []*pb.Vector{}
+	for _, block := range blocks {
+		for _, row := range block.GetElements() {
+			if len(rows) > 0 && len(row.Elements) != len(rows[0].Elements) {
+				return nil, errors.New("Failed to stack matrices")
+			}
+			rows = append(rows, row)
+		}
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	return toDense(&pb.Matrix{Elements: rows}), nil
+}
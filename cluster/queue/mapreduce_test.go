@@ -0,0 +1,57 @@
+package queue
+
+import (
+	"testing"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+func TestStackMatricesWorkerOrder(t *testing.T) {
+	results := []*matrix.DenseMatrix{
+		matrix.MakeDenseMatrixStacked([][]float64{{0, 0}}),
+		matrix.Zeros(0, 0),
+		matrix.MakeDenseMatrixStacked([][]float64{{2, 2}, {2, 3}}),
+		matrix.MakeDenseMatrixStacked([][]float64{{3, 3}}),
+	}
+	want := [][]float64{{0, 0}, {2, 2}, {2, 3}, {3, 3}}
+
+	for _, order := range [][]int{{0, 1, 2, 3}, {3, 1, 0, 2}, {2, 3, 1, 0}} {
+		blocks := make([]*pb.Matrix, len(results))
+		combine := collectMatrix(blocks)
+		for _, i := range order {
+			if err := combine(i, toProto(results[i])); err != nil {
+				t.Fatalf("order %v: unexpected error %v", order, err)
+			}
+		}
+		stacked, err := stackMatrices(blocks)
+		if err != nil || stacked == nil || stacked.Rows() != len(want) {
+			t.Errorf("order %v: stacked %v with error %v, want %v", order, stacked, err, want)
+			continue
+		}
+		for r, row := range want {
+			for c, x := range row {
+				if stacked.Get(r, c) != x {
+					t.Errorf("order %v: stacked %v, want %v", order, stacked, want)
+				}
+			}
+		}
+	}
+}
+
+func TestStackMatricesEmpty(t *testing.T) {
+	stacked, err := stackMatrices([]*pb.Matrix{&pb.Matrix{}, &pb.Matrix{}})
+	if err != nil || stacked != nil {
+		t.Errorf("stacked %v with error %v, want nil", stacked, err)
+	}
+}
+
+func TestStackMatricesInconsistent(t *testing.T) {
+	blocks := []*pb.Matrix{
+		toProto(matrix.MakeDenseMatrixStacked([][]float64{{1, 2}})),
+		toProto(matrix.MakeDenseMatrixStacked([][]float64{{1, 2, 3}})),
+	}
+	if _, err := stackMatrices(blocks); err == nil {
+		t.Errorf("expected an error for blocks with different numbers of columns")
+	}
+}
@@ -0,0 +1,81 @@
+package queue
+
+import (
+	"errors"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+// mergePCA approximates the principal components in a single round trip. Each
+// worker computes the top k components of its own partition and returns them
+// scaled by their singular values. The stacked components approximate the
+// data well in the directions that matter, so their singular value
+// decomposition gives a merged global subspace. Each worker sends k * cols
+// values instead of a cols x cols scatter matrix, which makes this useful as a
+// quick preview before running an exact job
+func mergePCA(job *Job, meanAndSD *pb.Matrix, cols int) (*matrix.DenseMatrix, []float64, error) {
+	k := job.Components
+	if k < 2 || k > cols {
+		grpclog.Printf("Invalid number of components: %v", k)
+		return nil, nil, errors.New("Invalid number of components")
+	}
+
+	err := standardize(job, meanAndSD)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	in := &pb.Components{
+		K: int32(k),
+	}
+	blocks := make([]*pb.Matrix, job.Workers)
+	err = execute(job, &phase{
+		name:    "GetLocalComponents",
+		failure: "Could not get local components",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetLocalComponents(context.Background(), in)
+		},
+		combine: collectMatrix(blocks),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	stacked, err := stackMatrices(blocks)
+	if err != nil {
+		return nil, nil, err
+	}
+	if stacked == nil {
+		return nil, nil, errors.New("Could not merge local components")
+	}
+
+	var eigenvectors *matrix.DenseMatrix
+	var singularValues []float64
+	if stacked.Rows() >= cols {
+		_, singularValues, eigenvectors, err = linalg.SVD(stacked)
+	} else {
+		eigenvectors, singularValues, _, err = linalg.SVD(stacked.Transpose())
+	}
+	if err != nil {
+		grpclog.Printf("Failed to compute SVD(): %v", err)
+		return nil, nil, errors.New("Could not merge local components")
+	}
+	if stacked.Rows() < cols {
+		linalg.OrientColumns(eigenvectors)
+	}
+	if len(singularValues) < k {
+		grpclog.Printf("Merged only %d components", len(singularValues))
+		return nil, nil, errors.New("Could not merge local components")
+	}
+
+	eigenvalues := make([]float64, k)
+	for i := range eigenvalues {
+		eigenvalues[i] = singularValues[i] * singularValues[i]
+	}
+
+	return eigenvectors.GetMatrix(0, 0, cols, k), eigenvalues, nil
+}
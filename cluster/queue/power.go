@@ -0,0 +1,136 @@
+package queue
+
+import (
+	"errors"
+	"math"
+
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+const (
+	// DefaultTolerance is the relative residual at which power iteration stops
+	DefaultTolerance = 1e-6
+	// DefaultMaxIterations bounds the number of power iteration passes
+	DefaultMaxIterations = 100
+)
+
+// powerPCA runs block power iteration over the workers to find the top
+// principal components without forming the scatter matrix. Each pass sends a
+// cols x l block of vectors and receives the scatter matrix applied to it,
+// from which the Ritz values and vectors of the current block are computed.
+// Iteration stops once the relative residual of the top components falls
+// below the job's tolerance or the iteration limit is reached. The number of
+// passes and the final residual are recorded in the response
+func powerPCA(job *Job, meanAndSD *pb.Matrix, cols int, resp *Response) (*matrix.DenseMatrix, []float64, error) {
+	k := job.Components
+	if k < 2 || k > cols {
+		grpclog.Printf("Invalid number of components: %v", k)
+		return nil, nil, errors.New("Invalid number of components")
+	}
+	if job.MaxIterations < 1 {
+		grpclog.Printf("Invalid number of iterations: %v", job.MaxIterations)
+		return nil, nil, errors.New("Invalid number of iterations")
+	}
+	l := k + oversampling
+	if l > cols {
+		l = cols
+	}
+
+	err := standardize(job, meanAndSD)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	basis, _, err := linalg.QR(randomBasis(cols, l))
+	if err != nil {
+		grpclog.Printf("Failed to compute QR(): %v", err)
+		return nil, nil, errors.New("Could not orthonormalize basis")
+	}
+
+	var ritzVectors *matrix.DenseMatrix
+	var ritzValues []float64
+	for iter := 1; iter <= job.MaxIterations; iter++ {
+		product, err := getRangeSketch(job, basis, cols, l)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		ritzVectors, ritzValues, resp.Residual, err = rayleighRitz(basis, product, k)
+		if err != nil {
+			grpclog.Printf("Failed to compute Rayleigh-Ritz step: %v", err)
+			return nil, nil, errors.New("Could not compute eigenvalues/vectors")
+		}
+		resp.Iterations = iter
+		if resp.Residual <= job.Tolerance {
+			break
+		}
+
+		basis, _, err = linalg.QR(product)
+		if err != nil {
+			grpclog.Printf("Failed to compute QR(): %v", err)
+			return nil, nil, errors.New("Could not orthonormalize basis")
+		}
+	}
+	if resp.Residual > job.Tolerance {
+		grpclog.Printf("Power iteration stopped after %d iterations with residual %v",
+			resp.Iterations, resp.Residual)
+	}
+
+	linalg.OrientColumns(ritzVectors)
+
+	return ritzVectors, ritzValues, nil
+}
+
+// rayleighRitz receives an orthonormal basis and the scatter matrix applied to
+// it and returns the top k Ritz vectors and values along with the largest
+// residual norm of those pairs relative to their Ritz value
+func rayleighRitz(basis *matrix.DenseMatrix, product *matrix.DenseMatrix, k int) (*matrix.DenseMatrix, []float64, float64, error) {
+	l := basis.Cols()
+
+	h, err := basis.Transpose().TimesDense(product)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	for i := 0; i < l; i++ {
+		for j := i + 1; j < l; j++ {
+			avg := (h.Get(i, j) + h.Get(j, i)) / 2
+			h.Set(i, j, avg)
+			h.Set(j, i, avg)
+		}
+	}
+
+	u, values, err := linalg.SymmetricEigen(h)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	u = u.GetMatrix(0, 0, l, k)
+
+	vectors, err := basis.TimesDense(u)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	applied, err := product.TimesDense(u)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	var residual float64
+	for j := 0; j < k; j++ {
+		var norm float64
+		for i := 0; i < vectors.Rows(); i++ {
+			diff := applied.Get(i, j) - values[j]*vectors.Get(i, j)
+			norm += diff * diff
+		}
+		norm = math.Sqrt(norm)
+		if values[j] != 0 {
+			norm /= math.Abs(values[j])
+		}
+		residual = math.Max(residual, norm)
+	}
+
+	return vectors, values[:k], residual, nil
+}
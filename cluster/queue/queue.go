@@ -1,6 +1,7 @@
 package queue
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"time"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/oleiade/lane"
 	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
 	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
 )
 
@@ -21,42 +23,157 @@ var (
 	processing = false
 )
 
-// Job represents a request from the front-end
-type Job struct {
-	Dataset         string
-	Workers         int
-	Standardize     bool
-	ResponseChannel chan *Response
-}
+// The analyses a job can run
+const (
+	// AnalysisPCA computes the principal components of the dataset
+	AnalysisPCA = "pca"
+	// AnalysisRegression fits a linear model predicting one column from the
+	// others
+	AnalysisRegression = "regression"
+	// AnalysisLDA finds the linear discriminants of the labeled classes
+	AnalysisLDA = "lda"
+	// AnalysisCCA finds the canonical correlations between two column groups
+	AnalysisCCA = "cca"
+	// AnalysisICA separates independent components after whitening the data
+	// with its principal components
+	AnalysisICA = "ica"
+	// AnalysisStatistics summarizes each column of the dataset
+	AnalysisStatistics = "stats"
+	// AnalysisCorrelation finds the correlations between the columns of the
+	// dataset and clusters the columns by them
+	AnalysisCorrelation = "correlation"
+)
 
-// Response represents what is returned to the front-end
-type Response struct {
-	Status          string      `json:"status"`
-	Message         string      `json:"message"`
-	Eigenvalues     []float64   `json:"eigenvalues"`
-	Eigenvectors    [][]float64 `json:"eigenvectors"`
-	PercentVariance float64     `json:"percentVariance"`
-	Elapsed         float64     `json:"elapsed"`
-}
+// The modes a job can compute its principal components with
+const (
+	// ModeExact eigen-decomposes the full scatter matrix
+	ModeExact = "exact"
+	// ModeRandomized approximates the top components from a randomized sketch
+	ModeRandomized = "randomized"
+	// ModePower iterates matrix-vector products until the top components converge
+	ModePower = "power"
+	// ModeTSQR takes the singular value decomposition of a tall-skinny QR factor
+	ModeTSQR = "tsqr"
+	// ModeMerge merges the top components computed locally by each worker
+	ModeMerge = "merge"
+	// ModeRobust down-weights outlying rows before eigen-decomposing the covariance
+	ModeRobust = "robust"
+	// ModeSparse finds components with a limited number of non-zero loadings
+	ModeSparse = "sparse"
+	// ModeEM fits probabilistic PCA with the EM algorithm, tolerating missing values
+	ModeEM = "em"
+	// ModeCrossValidate adds the cross-validated reconstruction error of each
+	// number of components to an exact job
+	ModeCrossValidate = "cv"
+)
 
-type sizeResponse struct {
-	Size  *pb.Size
-	Error error
+var modes = map[string]bool{
+	ModeExact:         true,
+	ModeRandomized:    true,
+	ModePower:         true,
+	ModeTSQR:          true,
+	ModeMerge:         true,
+	ModeRobust:        true,
+	ModeSparse:        true,
+	ModeEM:            true,
+	ModeCrossValidate: true,
 }
 
-type vectorResponse struct {
-	Vector *pb.Vector
-	Error  error
+// ValidMode returns whether a job can be run in the given mode
+func ValidMode(mode string) bool {
+	return modes[mode]
 }
 
-type matrixResponse struct {
-	Matrix *pb.Matrix
-	Error  error
+// Job represents a request from the front-end
+type Job struct {
+	Analysis            string
+	Dataset             string
+	Workers             int
+	Standardize         bool
+	Scaling             string // how standardized columns are centered and scaled
+	Shrinkage           string // the covariance is not shrunk when empty
+	Mode                string
+	Update              string
+	Components          int
+	Tolerance           float64
+	MaxIterations       int
+	Cardinality         int
+	Rotation            string
+	ParallelReplicates  int // parallel analysis is skipped when zero
+	BootstrapReplicates int // bootstrapping is skipped when zero
+	Folds               int
+	Clusters            int // clustering is skipped when zero
+	ClusterSpace        string
+	Target              int     // column predicted by a regression
+	Ridge               float64 // penalty on the regression coefficients
+	Groups              []string
+	ResponseChannel     chan *Response
+	timings             []*Timing
 }
 
-type dataFileResponse struct {
-	DataFile *pb.DataFile
-	Error    error
+// Response represents what is returned to the front-end
+type Response struct {
+	Status                string        `json:"status"`
+	Message               string        `json:"message"`
+	Analysis              string        `json:"analysis"`
+	Mode                  string        `json:"mode"`
+	Formulation           string        `json:"formulation"`
+	Rows                  int           `json:"rows"`
+	TotalWeight           float64       `json:"totalWeight"`
+	Eigenvalues           []float64     `json:"eigenvalues"`  // sorted in descending order
+	Eigenvectors          [][]float64   `json:"eigenvectors"` // one row per eigenvalue
+	SingularValues        []float64     `json:"singularValues"`
+	PercentVariance       float64       `json:"percentVariance"`
+	Iterations            int           `json:"iterations"`
+	Residual              float64       `json:"residual"`
+	RobustWeights         []float64     `json:"robustWeights"` // one per row in worker order
+	Features              [][]int       `json:"features"`      // non-zero loadings of each component
+	NoiseVariance         float64       `json:"noiseVariance"`
+	LogLikelihood         float64       `json:"logLikelihood"`
+	Shrinkage             string        `json:"shrinkage"`
+	ShrinkageCoefficient  float64       `json:"shrinkageCoefficient"` // weight of the scaled identity in the covariance
+	Rotation              string        `json:"rotation"`
+	RotationMatrix        [][]float64   `json:"rotationMatrix"`
+	RotatedLoadings       [][]float64   `json:"rotatedLoadings"` // one row per rotated component
+	RotatedVariance       []float64     `json:"rotatedVariance"`
+	CumulativeVariance    []float64     `json:"cumulativeVariance"` // percent of variance up to each component
+	KaiserComponents      int           `json:"kaiserComponents"`
+	BrokenStick           []float64     `json:"brokenStick"` // expected percent of variance of each component
+	ParallelThresholds    []float64     `json:"parallelThresholds"`
+	RecommendedComponents int           `json:"recommendedComponents"`
+	EigenvalueIntervals   [][]float64   `json:"eigenvalueIntervals"` // lower and upper bound of each eigenvalue
+	LoadingIntervals      [][][]float64 `json:"loadingIntervals"`    // bounds of each loading of the top components
+	CrossValidation       []float64     `json:"crossValidation"`     // held-out error keeping 1, 2, ... components
+	Centroids             [][]float64   `json:"centroids"`           // projected onto the top two components
+	ClusterSizes          []float64     `json:"clusterSizes"`        // total row weight of each cluster
+	ClusterCost           float64       `json:"clusterCost"`
+	ClusterIterations     int           `json:"clusterIterations"`
+	Target                int           `json:"target"`
+	Coefficients          []float64     `json:"coefficients"`   // one per column, zero for the target
+	StandardErrors        []float64     `json:"standardErrors"` // one per column, zero for the target
+	Intercept             float64       `json:"intercept"`
+	InterceptError        float64       `json:"interceptError"`
+	RSquared              float64       `json:"rSquared"`
+	AdjustedRSquared      float64       `json:"adjustedRSquared"`
+	Residuals             *Residuals    `json:"residuals"`
+	Classes               []float64     `json:"classes"`    // class labels in ascending order
+	ClassSizes            []float64     `json:"classSizes"` // total row weight of each class
+	ClassMeans            [][]float64   `json:"classMeans"` // projected onto the top two discriminants
+	Scores                [][]float64   `json:"scores"`     // discriminant or independent component scores and class of each row
+	Groups                []string      `json:"groups"`
+	GroupColumns          [][]int       `json:"groupColumns"`
+	CanonicalCorrelations []float64     `json:"canonicalCorrelations"` // sorted in descending order
+	CanonicalWeights      [][][]float64 `json:"canonicalWeights"`      // per group, one row per correlation
+	WhiteningMatrix       [][]float64   `json:"whiteningMatrix"`       // one row per whitened component
+	WhitenedUnmixing      [][]float64   `json:"whitenedUnmixing"`      // unmixes the whitened rows
+	UnmixingMatrix        [][]float64   `json:"unmixingMatrix"`        // unmixes the standardized rows
+	IndependentIterations int           `json:"independentIterations"`
+	Statistics            []*Summary    `json:"statistics"` // one per column
+	Correlation           [][]float64   `json:"correlation"`
+	FeatureOrder          []int         `json:"featureOrder"` // columns in the leaf order of their clustering
+	Dendrogram            [][]float64   `json:"dendrogram"`   // merged clusters, distance and size of each merge
+	Phases                []*Timing     `json:"phases"`
+	Elapsed               float64       `json:"elapsed"`
 }
 
 // Listen receives the worker addresses and a job channel
@@ -115,251 +232,414 @@ func Listen(addrs []string, jobc chan *Job) error {
 }
 
 func process(job *Job) {
-	var err error
-
-	resp := &Response{}
+	resp := &Response{
+		Analysis: job.Analysis,
+		Mode:     job.Mode,
+	}
 
 	if job.Workers > len(clients) {
 		grpclog.Printf("Invalid worker number: %v > %v", job.Workers, len(clients))
-		resp.Message = "Invalid worker number"
-		resp.Status = "error"
-		job.ResponseChannel <- resp
-		processing = false
+		fail(job, resp, "Invalid worker number")
 		return
 	}
 
 	grpclog.Println("Processing job")
 	startTime := time.Now()
 
-	sizec := make(chan sizeResponse)
-	var rows, cols int
-	for i := 0; i < job.Workers; i++ {
-		dataFile := &pb.DataFile{
-			Name: fmt.Sprintf("%s-%d-%d.csv", job.Dataset, job.Workers, i+1),
-		}
-		go func(client pb.WorkerClient) {
-			size, err := client.LoadData(context.Background(), dataFile)
-			sizec <- sizeResponse{
-				Size:  size,
-				Error: err,
-			}
-		}(clients[i])
+	var err error
+	switch job.Analysis {
+	case AnalysisRegression:
+		err = regression(job, resp)
+	case AnalysisLDA:
+		err = lda(job, resp)
+	case AnalysisCCA:
+		err = cca(job, resp)
+	case AnalysisStatistics:
+		err = statistics(job, resp)
+	case AnalysisCorrelation:
+		err = correlation(job, resp)
+	default:
+		err = pca(job, resp)
 	}
-	for i := 0; i < job.Workers; i++ {
-		sizeResp := <-sizec
-		size := sizeResp.Size
-		err := sizeResp.Error
-		if err != nil {
-			grpclog.Printf("%v.LoadData() got error %v", clients[i], err)
-			resp.Message = "Could not load data"
-			resp.Status = "error"
-			job.ResponseChannel <- resp
-			processing = false
-			return
-		}
-		if i == 0 {
-			cols = int(size.Cols)
-		} else if int(size.Cols) != cols {
-			grpclog.Printf("Inconsistent vector sizes: %v, %v", size.Cols, cols)
-			resp.Message = "Inconsistent vectors sizes"
-			resp.Status = "error"
-			job.ResponseChannel <- resp
-			processing = false
-			return
-		}
-		rows += int(size.Rows)
+	if err != nil {
+		fail(job, resp, err.Error())
+		return
 	}
 
-	sumc := make(chan vectorResponse)
-	sum := matrix.Zeros(1, cols)
-	for i := 0; i < job.Workers; i++ {
-		go func(client pb.WorkerClient) {
-			vector, err := client.GetSum(context.Background(), &pb.Unit{})
-			sumc <- vectorResponse{
-				Vector: vector,
-				Error:  err,
-			}
-		}(clients[i])
+	succeed(job, resp, startTime)
+}
+
+// pca computes the principal components of the dataset, or updates those of
+// the dataset already loaded, and records them in the response along with
+// the requested diagnostics, independent components and clusters
+func pca(job *Job, resp *Response) error {
+	var eigenvectors *matrix.DenseMatrix
+	var eigenvalues []float64
+	var totalVariance float64
+	var err error
+	if job.Update != "" {
+		eigenvectors, eigenvalues, totalVariance, err = updatePCA(job, resp)
+	} else {
+		eigenvectors, eigenvalues, totalVariance, err = computePCA(job, resp)
 	}
-	for i := 0; i < job.Workers; i++ {
-		vectorResp := <-sumc
-		subVector := vectorResp.Vector
-		err := vectorResp.Error
+	if err != nil {
+		return err
+	}
+	resp.Eigenvalues = eigenvalues
+	resp.Eigenvectors = eigenvectors.Transpose().Arrays()
+
+	if job.Rotation != "" {
+		err = rotate(job, resp, eigenvectors, eigenvalues)
 		if err != nil {
-			grpclog.Printf("%v.GetSum() got error %v", clients[i], err)
-			resp.Message = "Could not get sum"
-			resp.Status = "error"
-			job.ResponseChannel <- resp
-			processing = false
-			return
+			return err
 		}
-		subSum := matrix.MakeDenseMatrixStacked([][]float64{subVector.Elements})
-		sum.Add(subSum)
-	}
-	sumArray := sum.Array()
-	for i := range sumArray {
-		sumArray[i] /= float64(rows)
 	}
 
-	matrixc := make(chan matrixResponse)
-	mean := &pb.Vector{
-		Elements: sumArray,
-	}
+	topValues := resp.Eigenvalues[:2]
+	topVectors := resp.Eigenvectors[:2]
+	fmt.Println("top 1", topValues[0], topVectors[0])
+	fmt.Println("top 2", topValues[1], topVectors[1])
 
-	var sdArray []float64
-	if job.Standardize {
-		variancec := make(chan vectorResponse)
-		sdSum := matrix.Zeros(1, cols)
-		for i := 0; i < job.Workers; i++ {
-			go func(client pb.WorkerClient) {
-				vector, err := client.GetVariance(context.Background(), mean)
-				variancec <- vectorResponse{
-					Vector: vector,
-					Error:  err,
-				}
-			}(clients[i])
-		}
-		for i := 0; i < job.Workers; i++ {
-			vectorResp := <-variancec
-			varianceVector := vectorResp.Vector
-			err := vectorResp.Error
-			if err != nil {
-				grpclog.Printf("%v.GetVariance() got error %v", clients[i], err)
-				resp.Message = "Could not get variance"
-				resp.Status = "error"
-				job.ResponseChannel <- resp
-				processing = false
-				return
-			}
-			subSum := matrix.MakeDenseMatrixStacked([][]float64{varianceVector.Elements})
-			sdSum.Add(subSum)
+	resp.PercentVariance = 100 * (topValues[0] + topValues[1]) / totalVariance
+	diagnose(resp, eigenvalues, totalVariance, eigenvectors.Rows())
+
+	if job.Analysis == AnalysisICA {
+		err = ica(job, eigenvectors, eigenvalues, resp)
+		if err != nil {
+			return err
 		}
-		sdArray = sdSum.Array()
-		for i := range sdArray {
-			sdArray[i] = math.Sqrt(sdArray[i] / float64(rows))
+	}
+
+	top := &pb.Matrix{
+		Elements: []*pb.Vector{
+			&pb.Vector{Elements: topVectors[0]},
+			&pb.Vector{Elements: topVectors[1]},
+		},
+	}
+	if job.Clusters > 0 {
+		err = kmeans(job, top, resp)
+		if err != nil {
+			return err
 		}
-	} else {
-		sdArray = make([]float64, cols)
-		for i := range sdArray {
-			sdArray[i] = 1
+	}
+
+	// The rows of an EM job may hold missing values, which have no score
+	if save && job.Mode != ModeEM {
+		err = execute(job, &phase{
+			name:    "ComputeScores",
+			failure: "Could not compute scores",
+			task: func(i int, client pb.WorkerClient) (interface{}, error) {
+				return client.ComputeScores(context.Background(), top)
+			},
+		})
+		if err != nil {
+			return err
 		}
 	}
 
-	sd := &pb.Vector{
-		Elements: sdArray,
+	return nil
+}
+
+// computePCA loads the dataset on the workers and computes its principal
+// components with the job's mode. It returns the eigenvectors as columns along
+// with the eigenvalues and the total variance of the standardized data
+func computePCA(job *Job, resp *Response) (*matrix.DenseMatrix, []float64, float64, error) {
+	loaded = nil
+	rows, cols, weight, err := loadData(job)
+	if err != nil {
+		return nil, nil, 0, err
 	}
+	resp.Rows = rows
+	resp.TotalWeight = weight
 
-	meanAndSD := &pb.Matrix{
-		Elements: []*pb.Vector{mean, sd},
+	if job.Mode == ModeEM {
+		return emPCA(job, weight, cols, resp)
 	}
 
-	scatter := matrix.Zeros(cols, cols)
-	for i := 0; i < job.Workers; i++ {
-		go func(client pb.WorkerClient) {
-			matrix, err := client.GetScatterMatrix(context.Background(), meanAndSD)
-			matrixc <- matrixResponse{
-				Matrix: matrix,
-				Error:  err,
-			}
-		}(clients[i])
+	mean, variance, err := getMoments(job, weight, cols)
+	if err != nil {
+		return nil, nil, 0, err
 	}
-	for i := 0; i < job.Workers; i++ {
-		matrixResp := <-matrixc
-		subScatter := matrixResp.Matrix
-		err := matrixResp.Error
-		if err != nil {
-			grpclog.Printf("%v.GetScatterMatrix() got error %v", clients[i], err)
-			resp.Message = "Could not get scatter matrix"
-			resp.Status = "error"
-			job.ResponseChannel <- resp
-			processing = false
-			return
-		}
-		vectors := make([][]float64, cols)
-		for i := range subScatter.Elements {
-			vectors[i] = subScatter.Elements[i].Elements
+
+	sdArray := make([]float64, cols)
+	for i := range sdArray {
+		if job.Standardize {
+			sdArray[i] = math.Sqrt(variance[i] / weight)
+		} else {
+			sdArray[i] = 1
 		}
-		err = scatter.Add(matrix.MakeDenseMatrixStacked(vectors))
+	}
+
+	// Robust scaling centers the columns on their medians, so the scatter
+	// matrix is taken about the medians and its trace adds the squared
+	// offset of each mean
+	center := mean
+	if job.Standardize && job.Scaling == ScalingRobust {
+		center, sdArray, err = robustScaling(job, sdArray)
 		if err != nil {
-			grpclog.Printf("Failed to add matrices")
-			resp.Message = "Failed to add matrices"
-			resp.Status = "error"
-			job.ResponseChannel <- resp
-			processing = false
-			return
+			return nil, nil, 0, err
 		}
 	}
 
-	eigenvectors, eigenvaluesMatrix, err := scatter.Eigen()
-	if err != nil {
-		grpclog.Printf("Failed to compute Eigen(): %v", err)
-		resp.Message = "Could not compute eigenvalues/vectors"
-		resp.Status = "error"
-		job.ResponseChannel <- resp
-		processing = false
-		return
+	meanAndSD := &pb.Matrix{
+		Elements: []*pb.Vector{
+			&pb.Vector{Elements: center},
+			&pb.Vector{Elements: sdArray},
+		},
 	}
-	resp.Eigenvalues = eigenvaluesMatrix.DiagonalCopy()
-	resp.Eigenvectors = eigenvectors.Arrays()
 
-	var sumValues float64
-	topValues := []float64{-math.MaxFloat64, -math.MaxFloat64}
-	topVectors := make([]*matrix.DenseMatrix, 2)
-	for i, eigenvalue := range resp.Eigenvalues {
-		sumValues += eigenvalue
-		if eigenvalue < topValues[1] {
-			continue
+	var totalVariance float64
+	for i := range variance {
+		offset := mean[i] - center[i]
+		totalVariance += (variance[i] + weight*offset*offset) / (sdArray[i] * sdArray[i])
+	}
+
+	var eigenvectors *matrix.DenseMatrix
+	var eigenvalues []float64
+	switch job.Mode {
+	case ModeRandomized:
+		eigenvectors, eigenvalues, err = randomizedPCA(job, meanAndSD, cols)
+	case ModePower:
+		eigenvectors, eigenvalues, err = powerPCA(job, meanAndSD, cols, resp)
+	case ModeTSQR:
+		eigenvectors, eigenvalues, err = tsqrPCA(job, meanAndSD, cols, resp)
+	case ModeMerge:
+		eigenvectors, eigenvalues, err = mergePCA(job, meanAndSD, cols)
+	case ModeSparse:
+		eigenvectors, eigenvalues, err = sparsePCA(job, meanAndSD, cols, resp)
+	case ModeCrossValidate:
+		resp.Formulation = FormulationScatter
+		eigenvectors, eigenvalues, err = crossValidatePCA(job, meanAndSD, rows, weight, cols, resp)
+	case ModeRobust:
+		eigenvectors, eigenvalues, err = robustPCA(job, meanAndSD, cols, resp)
+		totalVariance = 0
+		for _, value := range eigenvalues {
+			totalVariance += value
 		}
-		if eigenvalue > topValues[0] {
-			topValues[1] = topValues[0]
-			topVectors[1] = topVectors[0]
-			topValues[0] = eigenvalue
-			topVectors[0] = eigenvectors.GetColVector(i).Transpose()
+	default:
+		// The shrunk covariance can only be formed from the scatter matrix
+		if rows < cols && job.Shrinkage == "" {
+			resp.Formulation = FormulationGram
+			eigenvectors, eigenvalues, err = dualPCA(job, meanAndSD, cols)
 		} else {
-			topValues[1] = eigenvalue
-			topVectors[1] = eigenvectors.GetColVector(i).Transpose()
+			resp.Formulation = FormulationScatter
+			eigenvectors, eigenvalues, err = exactPCA(job, meanAndSD, rows, weight, cols, resp)
 		}
 	}
-	fmt.Println("top 1", topValues[0], topVectors[0])
-	fmt.Println("top 2", topValues[1], topVectors[1])
-
-	resp.PercentVariance = 100 * (topValues[0] + topValues[1]) / sumValues
+	if err != nil {
+		return nil, nil, 0, err
+	}
 
-	if save {
-		top := &pb.Matrix{
-			Elements: []*pb.Vector{
-				&pb.Vector{Elements: topVectors[0].Array()},
-				&pb.Vector{Elements: topVectors[1].Array()},
-			},
-		}
-		filec := make(chan dataFileResponse)
-		for i := 0; i < job.Workers; i++ {
-			go func(client pb.WorkerClient) {
-				dataFile, err := client.ComputeScores(context.Background(), top)
-				filec <- dataFileResponse{
-					DataFile: dataFile,
-					Error:    err,
-				}
-			}(clients[i])
+	if job.ParallelReplicates > 0 {
+		err = parallelAnalysis(job, cols, resp)
+		if err != nil {
+			return nil, nil, 0, err
 		}
-		for i := 0; i < job.Workers; i++ {
-			fileResp := <-filec
-			err := fileResp.Error
-			if err != nil {
-				grpclog.Printf("%v.ComputeScores() got error %v", clients[i], err)
-				resp.Message = "Could not compute scores"
-				resp.Status = "error"
-				job.ResponseChannel <- resp
-				processing = false
-				return
-			}
+	}
+	if job.BootstrapReplicates > 0 {
+		err = bootstrap(job, eigenvectors, eigenvalues, cols, resp)
+		if err != nil {
+			return nil, nil, 0, err
 		}
 	}
 
+	return eigenvectors, eigenvalues, totalVariance, nil
+}
+
+// succeed sends the response of a finished job and frees the queue for the
+// next one
+func succeed(job *Job, resp *Response, startTime time.Time) {
 	endTime := time.Now()
 	resp.Elapsed = endTime.Sub(startTime).Seconds()
 	resp.Status = "ok"
+	resp.Phases = job.timings
 	job.ResponseChannel <- resp
+	processing = false
+}
 
+// fail sends an error response for the job and frees the queue for the next one
+func fail(job *Job, resp *Response, message string) {
+	resp.Message = message
+	resp.Status = "error"
+	resp.Phases = job.timings
+	job.ResponseChannel <- resp
 	processing = false
 }
+
+// loadData has each worker load its partition of the dataset and returns the
+// total number of rows along with the number of columns and the total weight
+// of the rows. Only EM jobs and statistics tolerate missing values, so any
+// other job fails on a dataset with missing values
+func loadData(job *Job) (int, int, float64, error) {
+	var rows, cols int
+	var weight float64
+	var missing int64
+	sizes := 0
+	err := execute(job, &phase{
+		name:    "LoadData",
+		failure: "Could not load data",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.LoadData(context.Background(), partition(job, job.Dataset, i))
+		},
+		combine: func(i int, result interface{}) error {
+			size := result.(*pb.Size)
+			if sizes == 0 {
+				cols = int(size.Cols)
+			} else if int(size.Cols) != cols {
+				return errors.New("Inconsistent vectors sizes")
+			}
+			sizes++
+			rows += int(size.Rows)
+			weight += size.Weight
+			missing += size.Missing
+			return nil
+		},
+		reduce: func() error {
+			if weight <= 0 {
+				grpclog.Printf("Invalid total weight: %v", weight)
+				return errors.New("Dataset has no weight")
+			}
+			if missing > 0 && job.Mode != ModeEM && job.Analysis != AnalysisStatistics {
+				grpclog.Printf("Dataset %s has %d missing values", job.Dataset, missing)
+				return errors.New("Dataset has missing values. Use EM mode")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	return rows, cols, weight, nil
+}
+
+// partition returns the data file holding worker i's share of the named file,
+// marked with the weight column of the job's dataset
+func partition(job *Job, name string, i int) *pb.DataFile {
+	schema := schemaFor(job.Dataset)
+	return &pb.DataFile{
+		Name:         fmt.Sprintf("%s-%d-%d.csv", name, job.Workers, i+1),
+		Weighted:     schema.WeightColumn != NoWeight,
+		WeightColumn: int32(schema.WeightColumn),
+	}
+}
+
+// getMoments returns the weighted mean of each column along with the weighted
+// sum of the squared deviations from that mean
+func getMoments(job *Job, weight float64, cols int) ([]float64, []float64, error) {
+	mean := make([]float64, cols)
+	variance := make([]float64, cols)
+	err := execute(job,
+		&phase{
+			name:    "GetSum",
+			failure: "Could not get sum",
+			task: func(i int, client pb.WorkerClient) (interface{}, error) {
+				return client.GetSum(context.Background(), &pb.Unit{})
+			},
+			combine: addVector(mean),
+			reduce: func() error {
+				for i := range mean {
+					mean[i] /= weight
+				}
+				return nil
+			},
+		},
+		&phase{
+			name:    "GetVariance",
+			failure: "Could not get variance",
+			task: func(i int, client pb.WorkerClient) (interface{}, error) {
+				return client.GetVariance(context.Background(), &pb.Vector{Elements: mean})
+			},
+			combine: addVector(variance),
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return mean, variance, nil
+}
+
+// standardize has each worker center and scale its partition in place
+func standardize(job *Job, meanAndSD *pb.Matrix) error {
+	return execute(job, &phase{
+		name:    "Standardize",
+		failure: "Could not standardize data",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.Standardize(context.Background(), meanAndSD)
+		},
+	})
+}
+
+// exactPCA sums the scatter matrices of the standardized partitions and
+// returns its eigenvectors as columns along with the eigenvalues. The summed
+// statistics are kept so that later updates can merge new rows into them,
+// before the covariance is shrunk if the job asks for it
+func exactPCA(job *Job, meanAndSD *pb.Matrix, rows int, weight float64, cols int, resp *Response) (*matrix.DenseMatrix, []float64, error) {
+	scatter, err := getScatterMatrix(job, meanAndSD, cols)
+	if err != nil {
+		return nil, nil, err
+	}
+	loaded = newModel(job, rows, weight, meanAndSD, scatter)
+
+	if job.Shrinkage != "" {
+		resp.Shrinkage = job.Shrinkage
+		resp.ShrinkageCoefficient, err = shrink(job, scatter, weight)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	eigenvectors, eigenvalues, err := linalg.SymmetricEigen(scatter)
+	if err != nil {
+		grpclog.Printf("Failed to compute SymmetricEigen(): %v", err)
+		return nil, nil, errors.New("Could not compute eigenvalues/vectors")
+	}
+
+	return eigenvectors, eigenvalues, nil
+}
+
+// getScatterMatrix standardizes each partition with the mean and standard
+// deviation and returns the sum of the scatter matrices of the partitions
+func getScatterMatrix(job *Job, meanAndSD *pb.Matrix, cols int) (*matrix.DenseMatrix, error) {
+	scatter := matrix.Zeros(cols, cols)
+	err := execute(job, &phase{
+		name:    "GetScatterMatrix",
+		failure: "Could not get scatter matrix",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetScatterMatrix(context.Background(), meanAndSD)
+		},
+		combine: addMatrix(scatter),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return scatter, nil
+}
+
+// toDense converts a protocol buffer matrix into a dense matrix. An empty
+// matrix converts to a matrix with no rows
+func toDense(m *pb.Matrix) *matrix.DenseMatrix {
+	if len(m.GetElements()) == 0 {
+		return matrix.Zeros(0, 0)
+	}
+	vectors := make([][]float64, len(m.Elements))
+	for i := range m.Elements {
+		vectors[i] = m.Elements[i].Elements
+	}
+	return matrix.MakeDenseMatrixStacked(vectors)
+}
+
+// toProto converts a dense matrix into a protocol buffer matrix
+func toProto(m *matrix.DenseMatrix) *pb.Matrix {
+	vectors := make([]*pb.Vector, m.Rows())
+	for i := range vectors {
+		vectors[i] = &pb.Vector{
+			Elements: m.GetRowVector(i).Array(),
+		}
+	}
+	return &pb.Matrix{
+		Elements: vectors,
+	}
+}
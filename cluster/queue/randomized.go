@@ -0,0 +1,133 @@
+package queue
+
+import (
+	"errors"
+	"math/rand"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+const (
+	// sketchSeed seeds the random test matrix so that results are reproducible
+	sketchSeed = 1
+	// oversampling is the number of extra columns added to the sketch
+	oversampling = 10
+	// powerIterations is the number of times the sketch is refined
+	powerIterations = 2
+)
+
+// randomizedPCA approximates the top principal components without forming the
+// scatter matrix. The coordinator sends a cols x l random test matrix and each
+// worker returns its cols x l share of the scatter matrix applied to it. The
+// orthonormalized sum spans (approximately) the top components, so projecting
+// the data onto it leaves only an l x l eigenproblem. Communication is
+// proportional to cols * l rather than cols * cols
+func randomizedPCA(job *Job, meanAndSD *pb.Matrix, cols int) (*matrix.DenseMatrix, []float64, error) {
+	k := job.Components
+	if k < 2 || k > cols {
+		grpclog.Printf("Invalid number of components: %v", k)
+		return nil, nil, errors.New("Invalid number of components")
+	}
+	l := k + oversampling
+	if l > cols {
+		l = cols
+	}
+
+	err := standardize(job, meanAndSD)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	basis := randomBasis(cols, l)
+
+	for iter := 0; iter <= powerIterations; iter++ {
+		var sketch *matrix.DenseMatrix
+		sketch, err = getRangeSketch(job, basis, cols, l)
+		if err != nil {
+			return nil, nil, err
+		}
+		basis, _, err = linalg.QR(sketch)
+		if err != nil {
+			grpclog.Printf("Failed to compute QR(): %v", err)
+			return nil, nil, errors.New("Could not orthonormalize sketch")
+		}
+	}
+
+	projected, err := getProjectedScatter(job, basis, l)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	smallVectors, eigenvalues, err := linalg.SymmetricEigen(projected)
+	if err != nil {
+		grpclog.Printf("Failed to compute SymmetricEigen(): %v", err)
+		return nil, nil, errors.New("Could not compute eigenvalues/vectors")
+	}
+
+	eigenvectors, err := basis.TimesDense(smallVectors.GetMatrix(0, 0, l, k))
+	if err != nil {
+		grpclog.Printf("Failed to lift eigenvectors: %v", err)
+		return nil, nil, errors.New("Could not compute eigenvalues/vectors")
+	}
+	linalg.OrientColumns(eigenvectors)
+
+	return eigenvectors, eigenvalues[:k], nil
+}
+
+// getRangeSketch returns the sum across workers of the scatter matrix of each
+// partition applied to the test matrix
+func getRangeSketch(job *Job, test *matrix.DenseMatrix, cols int, l int) (*matrix.DenseMatrix, error) {
+	in := toProto(test)
+	sketch := matrix.Zeros(cols, l)
+	err := execute(job, &phase{
+		name:    "GetRangeSketch",
+		failure: "Could not get range sketch",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetRangeSketch(context.Background(), in)
+		},
+		combine: addMatrix(sketch),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sketch, nil
+}
+
+// getProjectedScatter returns the l x l scatter matrix of the data projected
+// onto the orthonormal columns of the basis
+func getProjectedScatter(job *Job, basis *matrix.DenseMatrix, l int) (*matrix.DenseMatrix, error) {
+	in := toProto(basis)
+	scatter := matrix.Zeros(l, l)
+	err := execute(job, &phase{
+		name:    "GetProjectedScatter",
+		failure: "Could not get projected scatter matrix",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetProjectedScatter(context.Background(), in)
+		},
+		combine: addMatrix(scatter),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return scatter, nil
+}
+
+// randomBasis returns a cols x l matrix of standard normal samples drawn from
+// a fixed seed so that results are reproducible
+func randomBasis(cols int, l int) *matrix.DenseMatrix {
+	r := rand.New(rand.NewSource(sketchSeed))
+	basis := matrix.Zeros(cols, l)
+	for i := 0; i < cols; i++ {
+		for j := 0; j < l; j++ {
+			basis.Set(i, j, r.NormFloat64())
+		}
+	}
+	return basis
+}
@@ -0,0 +1,209 @@
+package queue
+
+import (
+	"errors"
+	"math"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+// Residuals summarizes the residuals of a regression over every row
+type Residuals struct {
+	Mean          float64 `json:"mean"`
+	SumOfSquares  float64 `json:"sumOfSquares"`
+	StandardError float64 `json:"standardError"` // estimate of the noise standard deviation
+	MeanAbsolute  float64 `json:"meanAbsolute"`
+	Min           float64 `json:"min"`
+	Max           float64 `json:"max"`
+}
+
+// regression loads the dataset on the workers and fits a linear model
+// predicting the job's target column from the other columns by least squares
+// with an optional ridge penalty. The workers return the scatter matrix of
+// the centered columns, scaled to unit variances when the job standardizes,
+// and the coefficients solve (Sxx + ridge I) b = Sxy on that scale before
+// being converted back to the units of the data along with an intercept. A
+// second pass has the workers summarize the residuals of their raw rows,
+// from which the noise variance, the standard errors of the coefficients and
+// R² are computed. Row weights count as frequencies throughout
+func regression(job *Job, resp *Response) error {
+	loaded = nil
+	rows, cols, weight, err := loadData(job)
+	if err != nil {
+		return err
+	}
+	resp.Rows = rows
+	resp.TotalWeight = weight
+
+	target := job.Target
+	p := cols - 1
+	if target < 0 || target >= cols || p < 1 {
+		grpclog.Printf("Invalid target column: %v", target)
+		return errors.New("Invalid target column")
+	}
+	if job.Ridge < 0 {
+		grpclog.Printf("Invalid ridge penalty: %v", job.Ridge)
+		return errors.New("Invalid ridge penalty")
+	}
+	df := weight - float64(p) - 1
+	if df <= 0 {
+		return errors.New("Not enough rows to fit the regression")
+	}
+	resp.Target = target
+
+	mean, variance, err := getMoments(job, weight, cols)
+	if err != nil {
+		return err
+	}
+	sdArray := make([]float64, cols)
+	for i := range sdArray {
+		if job.Standardize {
+			sdArray[i] = math.Sqrt(variance[i] / weight)
+		} else {
+			sdArray[i] = 1
+		}
+	}
+	meanAndSD := &pb.Matrix{
+		Elements: []*pb.Vector{
+			&pb.Vector{Elements: mean},
+			&pb.Vector{Elements: sdArray},
+		},
+	}
+	scatter, err := getScatterMatrix(job, meanAndSD, cols)
+	if err != nil {
+		return err
+	}
+
+	// Split the scatter matrix into the predictor block and the predictor
+	// column of the target, with the ridge penalty on the diagonal
+	predictors := make([]int, 0, p)
+	for j := 0; j < cols; j++ {
+		if j != target {
+			predictors = append(predictors, j)
+		}
+	}
+	sxx := matrix.Zeros(p, p)
+	sxy := matrix.Zeros(p, 1)
+	for a, i := range predictors {
+		for b, j := range predictors {
+			sxx.Set(a, b, scatter.Get(i, j))
+		}
+		sxy.Set(a, 0, scatter.Get(i, target))
+	}
+	penalized := sxx.Copy()
+	for a := 0; a < p; a++ {
+		penalized.Set(a, a, penalized.Get(a, a)+job.Ridge)
+	}
+	inverse, err := linalg.PseudoInverse(penalized)
+	if err != nil {
+		grpclog.Printf("Failed to compute PseudoInverse(): %v", err)
+		return errors.New("Could not solve normal equations")
+	}
+	scaled, err := inverse.TimesDense(sxy)
+	if err != nil {
+		grpclog.Printf("Failed to solve normal equations: %v", err)
+		return errors.New("Could not solve normal equations")
+	}
+
+	// Convert the coefficients back to the units of the data, where factor a
+	// maps a scaled coefficient of predictor a onto its unscaled coefficient
+	factors := make([]float64, p)
+	resp.Coefficients = make([]float64, cols)
+	resp.Intercept = mean[target]
+	for a, j := range predictors {
+		factors[a] = sdArray[target] / sdArray[j]
+		resp.Coefficients[j] = factors[a] * scaled.Get(a, 0)
+		resp.Intercept -= resp.Coefficients[j] * mean[j]
+	}
+
+	model := &pb.LinearModel{
+		Target:       int32(target),
+		Coefficients: &pb.Vector{Elements: resp.Coefficients},
+		Intercept:    resp.Intercept,
+	}
+	residuals, err := getResiduals(job, model)
+	if err != nil {
+		return err
+	}
+	noise := residuals.SumOfSquares / df
+	residuals.StandardError = math.Sqrt(noise)
+	resp.Residuals = residuals
+	if variance[target] > 0 {
+		resp.RSquared = 1 - residuals.SumOfSquares/variance[target]
+		resp.AdjustedRSquared = 1 - (1-resp.RSquared)*(weight-1)/df
+	}
+
+	// The scaled coefficients have covariance s² (Sxx + ridge I)^-1 Sxx
+	// (Sxx + ridge I)^-1, where s² is the noise variance on the scale of the
+	// target, which reduces to s² Sxx^-1 without a penalty
+	covariance, err := inverse.TimesDense(sxx)
+	if err == nil {
+		covariance, err = covariance.TimesDense(inverse)
+	}
+	if err != nil {
+		grpclog.Printf("Failed to compute coefficient covariance: %v", err)
+		return errors.New("Could not compute standard errors")
+	}
+	covariance.Scale(noise / (sdArray[target] * sdArray[target]))
+
+	resp.StandardErrors = make([]float64, cols)
+	interceptVariance := noise / weight
+	for a, i := range predictors {
+		resp.StandardErrors[i] = factors[a] * math.Sqrt(math.Max(covariance.Get(a, a), 0))
+		for b, j := range predictors {
+			interceptVariance += mean[i] * mean[j] * factors[a] * factors[b] * covariance.Get(a, b)
+		}
+	}
+	resp.InterceptError = math.Sqrt(math.Max(interceptVariance, 0))
+
+	return nil
+}
+
+// getResiduals merges the summaries of the residuals of the linear model
+// over the workers
+func getResiduals(job *Job, model *pb.LinearModel) (*Residuals, error) {
+	summary := &pb.ResidualSummary{
+		Min: math.Inf(1),
+		Max: math.Inf(-1),
+	}
+	err := execute(job, &phase{
+		name:    "GetResiduals",
+		failure: "Could not get residuals",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetResiduals(context.Background(), model)
+		},
+		combine: func(i int, result interface{}) error {
+			partial := result.(*pb.ResidualSummary)
+			if partial.Weight <= 0 {
+				return nil
+			}
+			summary.Weight += partial.Weight
+			summary.Sum += partial.Sum
+			summary.Squares += partial.Squares
+			summary.Absolute += partial.Absolute
+			summary.Min = math.Min(summary.Min, partial.Min)
+			summary.Max = math.Max(summary.Max, partial.Max)
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if summary.Weight <= 0 {
+		return nil, errors.New("Dataset has no weight")
+	}
+
+	residuals := &Residuals{
+		Mean:         summary.Sum / summary.Weight,
+		SumOfSquares: summary.Squares,
+		MeanAbsolute: summary.Absolute / summary.Weight,
+		Min:          summary.Min,
+		Max:          summary.Max,
+	}
+	return residuals, nil
+}
@@ -0,0 +1,105 @@
+package queue
+
+import (
+	"errors"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+const (
+	// permutationSeed seeds the column shuffles of the first parallel
+	// analysis replicate on the first worker
+	permutationSeed = 1
+	// parallelPercentile is the percentile of the permuted eigenvalues that an
+	// eigenvalue must exceed to be retained by parallel analysis
+	parallelPercentile = 0.95
+)
+
+// diagnose records the retention diagnostics of the eigenvalues in the
+// response: the cumulative percent of variance, the number of eigenvalues
+// above the average eigenvalue (Kaiser's criterion, which is an eigenvalue
+// above one for standardized data), and the percent of variance each
+// component is expected to explain under the broken-stick model. The
+// recommended number of components is the number of leading eigenvalues
+// above their parallel analysis thresholds when those were computed, and the
+// number of leading components explaining more than their broken-stick share
+// otherwise
+func diagnose(resp *Response, eigenvalues []float64, totalVariance float64, cols int) {
+	resp.CumulativeVariance = make([]float64, len(eigenvalues))
+	resp.KaiserComponents = 0
+	var cumulative float64
+	for i, value := range eigenvalues {
+		cumulative += value
+		resp.CumulativeVariance[i] = 100 * cumulative / totalVariance
+		if value > totalVariance/float64(cols) {
+			resp.KaiserComponents++
+		}
+	}
+
+	resp.BrokenStick = make([]float64, cols)
+	var expected float64
+	for i := cols; i > 0; i-- {
+		expected += 1 / float64(i)
+		resp.BrokenStick[i-1] = 100 * expected / float64(cols)
+	}
+
+	resp.RecommendedComponents = 0
+	for i, value := range eigenvalues {
+		if resp.ParallelThresholds != nil {
+			if i >= len(resp.ParallelThresholds) || value <= resp.ParallelThresholds[i] {
+				break
+			}
+		} else if 100*value/totalVariance <= resp.BrokenStick[i] {
+			break
+		}
+		resp.RecommendedComponents++
+	}
+}
+
+// parallelAnalysis runs Horn's parallel analysis on the standardized data
+// held by the workers. Each replicate has every worker shuffle its columns
+// independently and the eigenvalues of the summed scatter matrices are those
+// expected of data with the same column distributions but no correlation. The
+// 95th percentile of each eigenvalue across the replicates is recorded in the
+// response as the threshold a real eigenvalue must exceed to be retained
+func parallelAnalysis(job *Job, cols int, resp *Response) error {
+	replicates := make([][]float64, cols)
+	for replicate := 0; replicate < job.ParallelReplicates; replicate++ {
+		scatter := matrix.Zeros(cols, cols)
+		err := execute(job, &phase{
+			name:    "GetPermutedScatter",
+			failure: "Could not get permuted scatter matrix",
+			task: func(i int, client pb.WorkerClient) (interface{}, error) {
+				permutation := &pb.Permutation{
+					Seed: int64(permutationSeed + replicate*job.Workers + i),
+				}
+				return client.GetPermutedScatter(context.Background(), permutation)
+			},
+			combine: addMatrix(scatter),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, eigenvalues, err := linalg.SymmetricEigen(scatter)
+		if err != nil {
+			grpclog.Printf("Failed to compute SymmetricEigen(): %v", err)
+			return errors.New("Could not compute eigenvalues/vectors")
+		}
+		for i, value := range eigenvalues {
+			replicates[i] = append(replicates[i], value)
+		}
+	}
+
+	resp.ParallelThresholds = make([]float64, cols)
+	for i, values := range replicates {
+		resp.ParallelThresholds[i] = percentile(values, parallelPercentile)
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package queue
+
+import (
+	"errors"
+	"math"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+// chiSquareQuantile is the standard normal quantile of the chi-square
+// probability below which rows keep their full weight in robust mode
+const chiSquareQuantile = 1.959963984540054
+
+// robustPCA estimates a covariance matrix that outliers cannot dominate by
+// iteratively reweighting the rows. Each pass sends the current center and
+// inverse covariance to the workers, which down-weight rows whose Mahalanobis
+// distance exceeds the 97.5% chi-square cutoff and return the weighted sum and
+// scatter matrix about the center. The first pass uses no cutoff so it starts
+// from the classical estimates. Iteration stops once the center and the
+// covariance change by less than the job's tolerance or the iteration limit is
+// reached. The number of passes and the final robust weight of every row, in
+// worker order, are recorded in the response
+func robustPCA(job *Job, meanAndSD *pb.Matrix, cols int, resp *Response) (*matrix.DenseMatrix, []float64, error) {
+	if job.MaxIterations < 1 {
+		grpclog.Printf("Invalid number of iterations: %v", job.MaxIterations)
+		return nil, nil, errors.New("Invalid number of iterations")
+	}
+
+	err := standardize(job, meanAndSD)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	estimate := &pb.RobustEstimate{
+		Center:    &pb.Vector{Elements: make([]float64, cols)},
+		Precision: toProto(matrix.Eye(cols)),
+	}
+	var covariance *matrix.DenseMatrix
+	for iter := 1; iter <= job.MaxIterations; iter++ {
+		resp.Iterations = iter
+
+		center, next, err := getRobustMoments(job, estimate, cols)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		converged := covariance != nil && relativeChange(covariance, next) < job.Tolerance
+		for i, value := range center {
+			if math.Abs(value-estimate.Center.Elements[i]) >= job.Tolerance {
+				converged = false
+			}
+		}
+		covariance = next
+		if converged {
+			break
+		}
+
+		precision, err := linalg.PseudoInverse(covariance)
+		if err != nil {
+			grpclog.Printf("Failed to compute PseudoInverse(): %v", err)
+			return nil, nil, errors.New("Could not invert covariance matrix")
+		}
+		estimate = &pb.RobustEstimate{
+			Center:    &pb.Vector{Elements: center},
+			Precision: toProto(precision),
+			Cutoff:    chiSquareCutoff(cols),
+		}
+	}
+
+	eigenvectors, eigenvalues, err := linalg.SymmetricEigen(covariance)
+	if err != nil {
+		grpclog.Printf("Failed to compute SymmetricEigen(): %v", err)
+		return nil, nil, errors.New("Could not compute eigenvalues/vectors")
+	}
+	// Scale the eigenvalues to the total weight so that they are comparable
+	// with the eigenvalues of the scatter matrix in the other modes
+	for i := range eigenvalues {
+		eigenvalues[i] *= resp.TotalWeight
+	}
+
+	resp.RobustWeights, err = getRobustWeights(job)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return eigenvectors, eigenvalues, nil
+}
+
+// getRobustMoments runs a reweighting pass on the workers and returns the
+// weighted mean of the standardized rows along with their weighted covariance
+// about that mean
+func getRobustMoments(job *Job, estimate *pb.RobustEstimate, cols int) ([]float64, *matrix.DenseMatrix, error) {
+	var weight float64
+	sum := make([]float64, cols)
+	scatter := matrix.Zeros(cols, cols)
+	err := execute(job, &phase{
+		name:    "GetRobustMoments",
+		failure: "Could not get robust moments",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetRobustMoments(context.Background(), estimate)
+		},
+		combine: func(i int, result interface{}) error {
+			moments := result.(*pb.Moments)
+			if len(moments.Sum.Elements) != cols || len(moments.Scatter.Elements) != cols {
+				return errors.New("Inconsistent vectors sizes")
+			}
+			weight += moments.Weight
+			for j := range sum {
+				sum[j] += moments.Sum.Elements[j]
+			}
+			return addMatrix(scatter)(i, moments.Scatter)
+		},
+		reduce: func() error {
+			if weight <= 0 {
+				return errors.New("Dataset has no weight")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The workers' scatter is about the previous center, so shift it to the
+	// new weighted mean before scaling it to a covariance
+	center := make([]float64, cols)
+	delta := make([]float64, cols)
+	for i := range center {
+		center[i] = sum[i] / weight
+		delta[i] = center[i] - estimate.Center.Elements[i]
+	}
+	for i := range delta {
+		for j := range delta {
+			scatter.Set(i, j, (scatter.Get(i, j)-weight*delta[i]*delta[j])/weight)
+		}
+	}
+
+	return center, scatter, nil
+}
+
+// getRobustWeights collects the robust weight of every row from the last
+// reweighting pass, concatenated in worker order
+func getRobustWeights(job *Job) ([]float64, error) {
+	blocks := make([][]float64, job.Workers)
+	err := execute(job, &phase{
+		name:    "GetRobustWeights",
+		failure: "Could not get robust weights",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetRobustWeights(context.Background(), &pb.Unit{})
+		},
+		combine: func(i int, result interface{}) error {
+			blocks[i] = result.(*pb.Vector).Elements
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	weights := []float64{}
+	for _, block := range blocks {
+		weights = append(weights, block...)
+	}
+	return weights, nil
+}
+
+// chiSquareCutoff returns the square root of the 97.5% quantile of the
+// chi-square distribution with the given degrees of freedom using the
+// Wilson-Hilferty approximation
+func chiSquareCutoff(degrees int) float64 {
+	p := float64(degrees)
+	a := 2 / (9 * p)
+	return math.Sqrt(p * math.Pow(1-a+chiSquareQuantile*math.Sqrt(a), 3))
+}
+
+// relativeChange returns the Frobenius norm of the difference between two
+// matrices relative to the norm of the first
+func relativeChange(previous *matrix.DenseMatrix, next *matrix.DenseMatrix) float64 {
+	var diff, norm float64
+	for i := 0; i < previous.Rows(); i++ {
+		for j := 0; j < previous.Cols(); j++ {
+			d := next.Get(i, j) - previous.Get(i, j)
+			diff += d * d
+			norm += previous.Get(i, j) * previous.Get(i, j)
+		}
+	}
+	if norm == 0 {
+		return 0
+	}
+	return math.Sqrt(diff / norm)
+}
@@ -0,0 +1,37 @@
+package queue
+
+import (
+	"math"
+	"testing"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+)
+
+func TestRobustPCAStableCovariance(t *testing.T) {
+	// rows that are never down-weighted keep the classical covariance
+	scatter := matrix.MakeDenseMatrixStacked([][]float64{{8, 2, 0}, {2, 6, 2}, {0, 2, 4}})
+	useFakeWorkers(&fakeWorker{weight: 2, scatter: scatter})
+	job := &Job{Workers: 1, MaxIterations: 10, Tolerance: 1e-9}
+	resp := &Response{TotalWeight: 2}
+
+	eigenvectors, eigenvalues, err := robustPCA(job, nil, 3, resp)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if resp.Iterations != 2 {
+		t.Errorf("converged after %d iterations, want 2", resp.Iterations)
+	}
+	vectors, want, err := linalg.SymmetricEigen(scatter)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for j := range want {
+		if math.Abs(eigenvalues[j]-want[j]) > 1e-9 {
+			t.Errorf("eigenvalue %d is %v, want %v", j, eigenvalues[j], want[j])
+		}
+		if !sameDirection(eigenvectors.GetColVector(j), vectors.GetColVector(j)) {
+			t.Errorf("eigenvector %d is %v, want %v", j, eigenvectors.GetColVector(j), vectors.GetColVector(j))
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package queue
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+)
+
+// The rotations that can be applied to the top components of a job
+const (
+	// RotationVarimax is an orthogonal rotation maximizing the variance of the
+	// squared loadings of each component
+	RotationVarimax = "varimax"
+	// RotationPromax is an oblique rotation that raises the varimax loadings to
+	// a power and fits them by least squares
+	RotationPromax = "promax"
+)
+
+const (
+	// varimaxEpsilon is the relative increase of the varimax criterion below
+	// which the rotation stops
+	varimaxEpsilon = 1e-5
+	// varimaxSweeps bounds the number of varimax iterations
+	varimaxSweeps = 1000
+	// promaxPower is the power the varimax loadings are raised to by promax
+	promaxPower = 4
+)
+
+var rotations = map[string]bool{
+	RotationVarimax: true,
+	RotationPromax:  true,
+}
+
+// ValidRotation returns whether the top components of a job can be rotated
+// with the given method
+func ValidRotation(rotation string) bool {
+	return rotations[rotation]
+}
+
+// rotate applies the job's rotation to the loadings of its top components,
+// which are the eigenvectors scaled by the square roots of their eigenvalues,
+// and records the rotated loadings in the response ordered by their variance.
+// Promax is oblique, so its rotated components are not orthogonal and are
+// kept apart from the eigenvectors and eigenvalues, which stay the
+// orthonormal principal components used for the variance, scores and
+// clusters. The rotation matrix and the variance of each rotated component
+// are recorded in the response as well
+func rotate(job *Job, resp *Response, eigenvectors *matrix.DenseMatrix, eigenvalues []float64) error {
+	k := job.Components
+	if k < 2 || k > len(eigenvalues) {
+		grpclog.Printf("Invalid number of components: %v", k)
+		return errors.New("Invalid number of components")
+	}
+	cols := eigenvectors.Rows()
+
+	loadings := eigenvectors.GetMatrix(0, 0, cols, k).Copy()
+	for j := 0; j < k; j++ {
+		scale := math.Sqrt(math.Max(eigenvalues[j], 0))
+		for i := 0; i < cols; i++ {
+			loadings.Set(i, j, scale*loadings.Get(i, j))
+		}
+	}
+
+	var rotation *matrix.DenseMatrix
+	var err error
+	switch job.Rotation {
+	case RotationVarimax:
+		rotation, err = varimax(loadings)
+	case RotationPromax:
+		rotation, err = promax(loadings)
+	default:
+		grpclog.Printf("Invalid rotation: %s", job.Rotation)
+		return errors.New("Invalid rotation")
+	}
+	if err != nil {
+		grpclog.Printf("Failed to compute %s rotation: %v", job.Rotation, err)
+		return errors.New("Could not rotate components")
+	}
+
+	rotated, err := loadings.TimesDense(rotation)
+	if err != nil {
+		grpclog.Printf("Failed to rotate loadings: %v", err)
+		return errors.New("Could not rotate components")
+	}
+
+	variance := make([]float64, k)
+	indices := make([]int, k)
+	for j := range variance {
+		for i := 0; i < cols; i++ {
+			variance[j] += rotated.Get(i, j) * rotated.Get(i, j)
+		}
+		indices[j] = j
+	}
+	sort.Stable(byMagnitude{indices: indices, values: variance})
+
+	// Reorder the columns by variance and orient the rotated loadings,
+	// flipping the matching columns of the rotation matrix
+	ordered := matrix.Zeros(cols, k)
+	orderedRotation := matrix.Zeros(k, k)
+	for j, index := range indices {
+		for i := 0; i < cols; i++ {
+			ordered.Set(i, j, rotated.Get(i, index))
+		}
+		for i := 0; i < k; i++ {
+			orderedRotation.Set(i, j, rotation.Get(i, index))
+		}
+	}
+	signs := linalg.OrientColumns(ordered)
+	for j, sign := range signs {
+		for i := 0; i < k; i++ {
+			orderedRotation.Set(i, j, sign*orderedRotation.Get(i, j))
+		}
+	}
+
+	resp.RotatedLoadings = ordered.Transpose().Arrays()
+	resp.Rotation = job.Rotation
+	resp.RotationMatrix = orderedRotation.Arrays()
+	resp.RotatedVariance = variance
+
+	return nil
+}
+
+// varimax returns the orthogonal matrix rotating the loadings to maximize the
+// varimax criterion. The rows of the loadings are normalized by their
+// communalities first, as in Kaiser's original method
+func varimax(loadings *matrix.DenseMatrix) (*matrix.DenseMatrix, error) {
+	rows, k := loadings.GetSize()
+	normalized := loadings.Copy()
+	for i := 0; i < rows; i++ {
+		var communality float64
+		for j := 0; j < k; j++ {
+			communality += loadings.Get(i, j) * loadings.Get(i, j)
+		}
+		if communality == 0 {
+			continue
+		}
+		for j := 0; j < k; j++ {
+			normalized.Set(i, j, loadings.Get(i, j)/math.Sqrt(communality))
+		}
+	}
+
+	rotation := matrix.Eye(k)
+	var criterion float64
+	for sweep := 0; sweep < varimaxSweeps; sweep++ {
+		z, err := normalized.TimesDense(rotation)
+		if err != nil {
+			return nil, err
+		}
+		target := matrix.Zeros(rows, k)
+		for j := 0; j < k; j++ {
+			var squares float64
+			for i := 0; i < rows; i++ {
+				squares += z.Get(i, j) * z.Get(i, j)
+			}
+			for i := 0; i < rows; i++ {
+				x := z.Get(i, j)
+				target.Set(i, j, x*x*x-x*squares/float64(rows))
+			}
+		}
+		gradient, err := normalized.Transpose().TimesDense(target)
+		if err != nil {
+			return nil, err
+		}
+
+		u, singularValues, v, err := linalg.SVD(gradient)
+		if err != nil {
+			return nil, err
+		}
+		if singularValues[k-1] <= 0 {
+			break
+		}
+		rotation, err = u.TimesDense(v.Transpose())
+		if err != nil {
+			return nil, err
+		}
+
+		previous := criterion
+		criterion = 0
+		for _, value := range singularValues {
+			criterion += value
+		}
+		if criterion < previous*(1+varimaxEpsilon) {
+			break
+		}
+	}
+
+	return rotation, nil
+}
+
+// promax returns the oblique matrix rotating the loadings towards their
+// varimax rotation raised to the promax power. The columns of the fitted
+// transformation are scaled so that the rotated components have unit variance
+func promax(loadings *matrix.DenseMatrix) (*matrix.DenseMatrix, error) {
+	orthogonal, err := varimax(loadings)
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := loadings.TimesDense(orthogonal)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, k := rotated.GetSize()
+	target := matrix.Zeros(rows, k)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < k; j++ {
+			x := rotated.Get(i, j)
+			target.Set(i, j, x*math.Pow(math.Abs(x), promaxPower-1))
+		}
+	}
+
+	// Least squares fit of the target: (L^T L)^-1 L^T P
+	gram, err := rotated.Transpose().TimesDense(rotated)
+	if err != nil {
+		return nil, err
+	}
+	inverse, err := linalg.PseudoInverse(gram)
+	if err != nil {
+		return nil, err
+	}
+	projection, err := rotated.Transpose().TimesDense(target)
+	if err != nil {
+		return nil, err
+	}
+	fit, err := inverse.TimesDense(projection)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := fit.Transpose().TimesDense(fit)
+	if err != nil {
+		return nil, err
+	}
+	scale, err := linalg.PseudoInverse(product)
+	if err != nil {
+		return nil, err
+	}
+	for j := 0; j < k; j++ {
+		factor := math.Sqrt(scale.Get(j, j))
+		for i := 0; i < k; i++ {
+			fit.Set(i, j, factor*fit.Get(i, j))
+		}
+	}
+
+	return orthogonal.TimesDense(fit)
+}
@@ -0,0 +1,57 @@
+package queue
+
+import (
+	"errors"
+	"math"
+
+	"google.golang.org/grpc/grpclog"
+)
+
+// The scalings a job can standardize its columns with
+const (
+	// ScalingSD centers each column on its mean and scales it by its
+	// standard deviation
+	ScalingSD = "sd"
+	// ScalingRobust centers each column on its median and scales it by its
+	// interquartile range, so that outlying values have little influence on
+	// the scaling
+	ScalingRobust = "robust"
+)
+
+var scalings = map[string]bool{
+	ScalingSD:     true,
+	ScalingRobust: true,
+}
+
+// ValidScaling returns whether a job can standardize its columns with the
+// given scaling
+func ValidScaling(scaling string) bool {
+	return scalings[scaling]
+}
+
+// robustScaling returns the median and the interquartile range of each column
+// estimated from the merged quantile sketches of the workers. A column whose
+// interquartile range is zero keeps the given standard deviation as its scale
+func robustScaling(job *Job, sd []float64) ([]float64, []float64, error) {
+	cols := len(sd)
+	digests, _, err := getColumnSketches(job, make([]float64, cols), make([]float64, cols), 1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	median := make([]float64, cols)
+	scale := make([]float64, cols)
+	for j, digest := range digests {
+		if digest.Weight() <= 0 {
+			grpclog.Printf("Column %d has no observed entries", j)
+			return nil, nil, errors.New("Cannot scale column without entries")
+		}
+		median[j] = digest.Quantile(0.5)
+		scale[j] = digest.Quantile(0.75) - digest.Quantile(0.25)
+		if scale[j] <= 0 || math.IsNaN(scale[j]) {
+			scale[j] = sd[j]
+		}
+	}
+
+	return median, scale, nil
+}
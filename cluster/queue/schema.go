@@ -0,0 +1,67 @@
+package queue
+
+// NoWeight marks a dataset whose rows are all equally weighted
+const NoWeight = -1
+
+// Schema describes how the columns of a dataset are interpreted
+type Schema struct {
+	// WeightColumn is the index of the column holding the weight of each row,
+	// or NoWeight. The weight column is not treated as a feature
+	WeightColumn int
+	// Groups names sets of related features by their indices among the
+	// features, so after any weight column is removed
+	Groups map[string][]int
+}
+
+var schemas = map[string]*Schema{
+	"iris": &Schema{
+		WeightColumn: NoWeight,
+		Groups: map[string][]int{
+			"sepal": {0, 1},
+			"petal": {2, 3},
+		},
+	},
+	// The credit card features follow the order of CreditCardFeatures in the
+	// server, with the categorical columns of the original dataset one-hot
+	// encoded and the months running from September back to April 2005
+	"credit-card": &Schema{
+		WeightColumn: NoWeight,
+		Groups: map[string][]int{
+			// amount of credit (0), gender male/female (1-2), education
+			// graduate/university/high school/other (3-6), marital status
+			// married/single/other (7-9) and age (10)
+			"profile": {0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			// months each payment was delayed
+			"history": {11, 12, 13, 14, 15, 16},
+			// amounts of the bill statements
+			"bills": {17, 18, 19, 20, 21, 22},
+			// amounts of the previous payments
+			"payments": {23, 24, 25, 26, 27, 28},
+		},
+	},
+}
+
+// SetSchema records the schema of a dataset
+func SetSchema(dataset string, schema *Schema) {
+	schemas[dataset] = schema
+}
+
+// SetWeightColumn records the weight column of a dataset, keeping the rest of
+// its schema
+func SetWeightColumn(dataset string, column int) {
+	schema, ok := schemas[dataset]
+	if !ok {
+		schema = &Schema{}
+		schemas[dataset] = schema
+	}
+	schema.WeightColumn = column
+}
+
+// schemaFor returns the schema of a dataset, which defaults to unweighted rows
+func schemaFor(dataset string) *Schema {
+	schema, ok := schemas[dataset]
+	if !ok {
+		return &Schema{WeightColumn: NoWeight}
+	}
+	return schema
+}
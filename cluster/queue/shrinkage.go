@@ -0,0 +1,108 @@
+package queue
+
+import (
+	"errors"
+	"math"
+
+	"golang.org/x/net/context"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+// The estimators a job can shrink the covariance of the standardized data with
+const (
+	// ShrinkageLedoitWolf picks the shrinkage towards a scaled identity that
+	// minimizes the expected squared error of the covariance, estimated from
+	// how much the outer products of the rows vary
+	ShrinkageLedoitWolf = "ledoit-wolf"
+	// ShrinkageOAS picks the oracle approximating shrinkage of Chen et al,
+	// which assumes Gaussian data and shrinks less noisy estimates less
+	ShrinkageOAS = "oas"
+)
+
+var shrinkages = map[string]bool{
+	ShrinkageLedoitWolf: true,
+	ShrinkageOAS:        true,
+}
+
+// ValidShrinkage returns whether a job can shrink its covariance with the
+// given estimator
+func ValidShrinkage(shrinkage string) bool {
+	return shrinkages[shrinkage]
+}
+
+// shrink replaces the scatter matrix of the standardized data, in place, with
+// the total weight times the shrunk covariance (1 - ρ) S + ρ μ I, where S is
+// the sample covariance and μ the average of its eigenvalues, and returns the
+// shrinkage coefficient ρ chosen by the job's estimator. Shrinking towards a
+// scaled identity keeps the eigenvectors and the total variance but pulls the
+// eigenvalues towards their average, which counters their spread when there
+// are few rows relative to the columns
+func shrink(job *Job, scatter *matrix.DenseMatrix, weight float64) (float64, error) {
+	p := float64(scatter.Rows())
+	if p == 0 || weight <= 0 {
+		return 0, errors.New("Dataset has no weight")
+	}
+
+	var trace, squares float64
+	for i := 0; i < scatter.Rows(); i++ {
+		trace += scatter.Get(i, i) / weight
+		for j := 0; j < scatter.Cols(); j++ {
+			squares += math.Pow(scatter.Get(i, j)/weight, 2)
+		}
+	}
+	mu := trace / p
+
+	var coefficient float64
+	switch job.Shrinkage {
+	case ShrinkageLedoitWolf:
+		fourth, err := getFourthMoment(job)
+		if err != nil {
+			return 0, err
+		}
+		// d² is the squared distance of S from the target and b² the
+		// variance of the outer products of the rows around S divided by
+		// the total weight, which is capped at d²
+		d2 := squares - mu*mu*p
+		b2 := math.Min(math.Max((fourth/weight-squares)/weight, 0), d2)
+		if d2 > 0 {
+			coefficient = b2 / d2
+		}
+	case ShrinkageOAS:
+		alpha := squares / (p * p)
+		denominator := (weight + 1) * (alpha - mu*mu/p)
+		coefficient = 1
+		if denominator > 0 {
+			coefficient = math.Min((alpha+mu*mu)/denominator, 1)
+		}
+	default:
+		return 0, errors.New("Invalid shrinkage")
+	}
+
+	scatter.Scale(1 - coefficient)
+	for i := 0; i < scatter.Rows(); i++ {
+		scatter.Set(i, i, scatter.Get(i, i)+coefficient*mu*weight)
+	}
+
+	return coefficient, nil
+}
+
+// getFourthMoment returns the weighted sum over the standardized rows of the
+// fourth power of their norms
+func getFourthMoment(job *Job) (float64, error) {
+	sum := make([]float64, 1)
+	err := execute(job, &phase{
+		name:    "GetFourthMoment",
+		failure: "Could not get fourth moment",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetFourthMoment(context.Background(), &pb.Unit{})
+		},
+		combine: addVector(sum),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return sum[0], nil
+}
@@ -0,0 +1,187 @@
+package queue
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+// sparsePCA computes components with at most the job's cardinality of
+// non-zero loadings using the truncated power method of Yuan and Zhang. Each
+// component starts from the truncated leading eigenvector of the scatter
+// matrix and repeatedly multiplies by the scatter matrix, keeping only the
+// largest loadings in magnitude, until it changes by less than the job's
+// tolerance. The scatter matrix is then deflated by projecting out the
+// component before the next one is found. The total number of passes and the
+// features each component uses are recorded in the response
+func sparsePCA(job *Job, meanAndSD *pb.Matrix, cols int, resp *Response) (*matrix.DenseMatrix, []float64, error) {
+	k := job.Components
+	if k < 2 || k > cols {
+		grpclog.Printf("Invalid number of components: %v", k)
+		return nil, nil, errors.New("Invalid number of components")
+	}
+	if job.MaxIterations < 1 {
+		grpclog.Printf("Invalid number of iterations: %v", job.MaxIterations)
+		return nil, nil, errors.New("Invalid number of iterations")
+	}
+	cardinality := job.Cardinality
+	if cardinality == 0 {
+		cardinality = cols
+	}
+	if cardinality < 1 || cardinality > cols {
+		grpclog.Printf("Invalid cardinality: %v", job.Cardinality)
+		return nil, nil, errors.New("Invalid cardinality")
+	}
+
+	scatter, err := getScatterMatrix(job, meanAndSD, cols)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	components := matrix.Zeros(cols, k)
+	eigenvalues := make([]float64, k)
+	resp.Features = make([][]int, k)
+	for j := 0; j < k; j++ {
+		vectors, _, err := linalg.SymmetricEigen(scatter)
+		if err != nil {
+			grpclog.Printf("Failed to compute SymmetricEigen(): %v", err)
+			return nil, nil, errors.New("Could not compute eigenvalues/vectors")
+		}
+		component := truncate(vectors.GetColVector(0), cardinality)
+		if component == nil {
+			grpclog.Printf("Scatter matrix vanished after %d components", j)
+			return nil, nil, errors.New("Not enough components")
+		}
+
+		for iter := 1; iter <= job.MaxIterations; iter++ {
+			resp.Iterations++
+			product, err := scatter.TimesDense(component)
+			if err != nil {
+				grpclog.Printf("Failed to multiply matrices: %v", err)
+				return nil, nil, errors.New("Could not compute sparse components")
+			}
+			next := truncate(product, cardinality)
+			if next == nil {
+				break
+			}
+			var change float64
+			for i := 0; i < cols; i++ {
+				change += math.Abs(next.Get(i, 0) - component.Get(i, 0))
+			}
+			component = next
+			if change < job.Tolerance {
+				break
+			}
+		}
+
+		product, err := scatter.TimesDense(component)
+		if err != nil {
+			grpclog.Printf("Failed to multiply matrices: %v", err)
+			return nil, nil, errors.New("Could not compute sparse components")
+		}
+		for i := 0; i < cols; i++ {
+			value := component.Get(i, 0)
+			components.Set(i, j, value)
+			eigenvalues[j] += value * product.Get(i, 0)
+			if value != 0 {
+				resp.Features[j] = append(resp.Features[j], i)
+			}
+		}
+
+		scatter, err = deflate(scatter, component)
+		if err != nil {
+			grpclog.Printf("Failed to deflate scatter matrix: %v", err)
+			return nil, nil, errors.New("Could not compute sparse components")
+		}
+	}
+	linalg.OrientColumns(components)
+
+	return components, eigenvalues, nil
+}
+
+// truncate keeps the given number of largest entries in magnitude of a column
+// vector, zeroes the rest and normalizes the result. It returns nil if the
+// kept entries are all zero
+func truncate(v *matrix.DenseMatrix, cardinality int) *matrix.DenseMatrix {
+	n := v.Rows()
+	values := make([]float64, n)
+	indices := make([]int, n)
+	for i := range values {
+		values[i] = math.Abs(v.Get(i, 0))
+		indices[i] = i
+	}
+	sort.Stable(byMagnitude{indices: indices, values: values})
+
+	var norm float64
+	for _, i := range indices[:cardinality] {
+		norm += v.Get(i, 0) * v.Get(i, 0)
+	}
+	if norm == 0 {
+		return nil
+	}
+	norm = math.Sqrt(norm)
+
+	truncated := matrix.Zeros(n, 1)
+	for _, i := range indices[:cardinality] {
+		truncated.Set(i, 0, v.Get(i, 0)/norm)
+	}
+	return truncated
+}
+
+// deflate projects a unit vector out of both sides of a symmetric matrix,
+// returning (I - vv^T) m (I - vv^T)
+func deflate(m *matrix.DenseMatrix, v *matrix.DenseMatrix) (*matrix.DenseMatrix, error) {
+	projection := matrix.Eye(m.Rows())
+	outer, err := v.TimesDense(v.Transpose())
+	if err != nil {
+		return nil, err
+	}
+	err = projection.SubtractDense(outer)
+	if err != nil {
+		return nil, err
+	}
+
+	left, err := projection.TimesDense(m)
+	if err != nil {
+		return nil, err
+	}
+	deflated, err := left.TimesDense(projection)
+	if err != nil {
+		return nil, err
+	}
+
+	// Restore exact symmetry lost to rounding so the eigen solver accepts it
+	for i := 0; i < deflated.Rows(); i++ {
+		for j := i + 1; j < deflated.Cols(); j++ {
+			mean := (deflated.Get(i, j) + deflated.Get(j, i)) / 2
+			deflated.Set(i, j, mean)
+			deflated.Set(j, i, mean)
+		}
+	}
+	return deflated, nil
+}
+
+// byMagnitude sorts indices by their values in descending order
+type byMagnitude struct {
+	indices []int
+	values  []float64
+}
+
+func (b byMagnitude) Len() int {
+	return len(b.indices)
+}
+
+func (b byMagnitude) Less(i, j int) bool {
+	return b.values[i] > b.values[j]
+}
+
+func (b byMagnitude) Swap(i, j int) {
+	b.indices[i], b.indices[j] = b.indices[j], b.indices[i]
+	b.values[i], b.values[j] = b.values[j], b.values[i]
+}
@@ -0,0 +1,33 @@
+package queue
+
+import (
+	"math"
+	"testing"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+)
+
+func TestSparsePCAFullCardinality(t *testing.T) {
+	// without truncation the sparse components are the leading eigenvectors
+	scatter := matrix.MakeDenseMatrixStacked([][]float64{{4, 1, 0}, {1, 3, 1}, {0, 1, 2}})
+	useFakeWorkers(&fakeWorker{weight: 1, scatter: scatter})
+	job := &Job{Workers: 1, Components: 2, MaxIterations: 1000, Tolerance: 1e-12}
+
+	components, values, err := sparsePCA(job, nil, 3, &Response{})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	vectors, want, err := linalg.SymmetricEigen(scatter)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for j := range values {
+		if math.Abs(values[j]-want[j]) > 1e-6 {
+			t.Errorf("eigenvalue %d is %v, want %v", j, values[j], want[j])
+		}
+		if !sameDirection(components.GetColVector(j), vectors.GetColVector(j)) {
+			t.Errorf("component %d is %v, want %v", j, components.GetColVector(j), vectors.GetColVector(j))
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package queue
+
+import (
+	"errors"
+	"math"
+
+	"golang.org/x/net/context"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+	"github.com/unchartedsoftware/rannu/cluster/sketch"
+)
+
+// histogramBins is the number of equal width bins of each column histogram
+const histogramBins = 20
+
+// Summary holds the descriptive statistics of one column of a dataset.
+// The statistics other than the missing count are over the observed entries
+// and are zero for a column without any
+type Summary struct {
+	Weight   float64 `json:"weight"`  // total weight of the observed entries
+	Missing  int     `json:"missing"` // number of missing entries
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	Mean     float64 `json:"mean"`
+	SD       float64 `json:"sd"`
+	Skewness float64 `json:"skewness"`
+	Kurtosis float64 `json:"kurtosis"` // excess kurtosis, zero for a normal distribution
+	Median   float64 `json:"median"`
+	Q1       float64 `json:"q1"` // lower quartile
+	Q3       float64 `json:"q3"` // upper quartile
+
+	// Histogram holds the total weight of the entries in each of the equal
+	// width bins between the smallest and the largest entry
+	Histogram []float64 `json:"histogram"`
+}
+
+// StatisticsWorkers returns the number of workers a job summarizing a whole
+// dataset is split over, which is the largest partitioning of the datasets
+// that the available workers can hold
+func StatisticsWorkers() int {
+	for _, workers := range []int{8, 4, 2} {
+		if workers <= len(clients) {
+			return workers
+		}
+	}
+	return 1
+}
+
+// statistics loads the dataset on the workers and summarizes each of its
+// columns with its moments, its quartiles and a histogram
+func statistics(job *Job, resp *Response) error {
+	loaded = nil
+	rows, cols, weight, err := loadData(job)
+	if err != nil {
+		return err
+	}
+	resp.Rows = rows
+	resp.TotalWeight = weight
+
+	merged, err := getColumnStatistics(job, cols)
+	if err != nil {
+		return err
+	}
+	digests, histograms, err := getColumnSketches(job, merged.Min, merged.Max, histogramBins)
+	if err != nil {
+		return err
+	}
+
+	resp.Statistics = make([]*Summary, cols)
+	for j := range resp.Statistics {
+		summary := &Summary{
+			Weight:  merged.Weight[j],
+			Missing: int(merged.Missing[j]),
+		}
+		if merged.Weight[j] > 0 {
+			variance := merged.M2[j] / merged.Weight[j]
+			summary.Min = merged.Min[j]
+			summary.Max = merged.Max[j]
+			summary.Mean = merged.Mean[j]
+			summary.SD = math.Sqrt(variance)
+			if variance > 0 {
+				summary.Skewness = merged.M3[j] / merged.Weight[j] / math.Pow(variance, 1.5)
+				summary.Kurtosis = merged.M4[j]/merged.Weight[j]/(variance*variance) - 3
+			}
+			summary.Median = digests[j].Quantile(0.5)
+			summary.Q1 = digests[j].Quantile(0.25)
+			summary.Q3 = digests[j].Quantile(0.75)
+			summary.Histogram = histograms.GetRowVector(j).Array()
+		}
+		resp.Statistics[j] = summary
+	}
+
+	return nil
+}
+
+// getColumnStatistics returns the moments of each column. The workers return
+// the central moments of their partitions, which are merged pairwise with the
+// update of Pébay that extends Chan et al to the third and fourth moments
+func getColumnStatistics(job *Job, cols int) (*pb.ColumnStatistics, error) {
+	merged := &pb.ColumnStatistics{
+		Weight:  make([]float64, cols),
+		Missing: make([]int64, cols),
+		Min:     make([]float64, cols),
+		Max:     make([]float64, cols),
+		Mean:    make([]float64, cols),
+		M2:      make([]float64, cols),
+		M3:      make([]float64, cols),
+		M4:      make([]float64, cols),
+	}
+	for j := 0; j < cols; j++ {
+		merged.Min[j] = math.Inf(1)
+		merged.Max[j] = math.Inf(-1)
+	}
+	err := execute(job, &phase{
+		name:    "GetColumnStatistics",
+		failure: "Could not get column statistics",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetColumnStatistics(context.Background(), &pb.Unit{})
+		},
+		combine: func(i int, result interface{}) error {
+			partial := result.(*pb.ColumnStatistics)
+			if len(partial.Weight) != cols || len(partial.Missing) != cols || len(partial.M4) != cols {
+				return errors.New("Inconsistent vectors sizes")
+			}
+			for j := 0; j < cols; j++ {
+				mergeColumn(merged, partial, j)
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// getColumnSketches returns the merged t-digest of each column along with the
+// cols x bins matrix of the total weight of its entries in each of the equal
+// width bins between the given smallest and largest values
+func getColumnSketches(job *Job, min []float64, max []float64, bins int) ([]*sketch.Digest, *matrix.DenseMatrix, error) {
+	cols := len(min)
+	binning := &pb.Binning{
+		Bins:        int32(bins),
+		Min:         &pb.Vector{Elements: min},
+		Max:         &pb.Vector{Elements: max},
+		Compression: sketch.DefaultCompression,
+	}
+	digests := make([]*sketch.Digest, cols)
+	for j := range digests {
+		digests[j] = sketch.New(sketch.DefaultCompression)
+	}
+	histograms := matrix.Zeros(cols, bins)
+	err := execute(job, &phase{
+		name:    "GetColumnSketches",
+		failure: "Could not get column sketches",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetColumnSketches(context.Background(), binning)
+		},
+		combine: func(i int, result interface{}) error {
+			sketches := result.(*pb.ColumnSketches)
+			if len(sketches.Digests) != cols {
+				return errors.New("Inconsistent vectors sizes")
+			}
+			for j, d := range sketches.Digests {
+				if len(d.Means) != len(d.Weights) {
+					return errors.New("Inconsistent digest sizes")
+				}
+				digests[j].Merge(sketch.FromCentroids(sketch.DefaultCompression, d.Means, d.Weights, d.Min, d.Max))
+			}
+			return addMatrix(histograms)(i, sketches.Histograms)
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return digests, histograms, nil
+}
+
+// mergeColumn merges the moments of column j of the partial statistics into
+// the merged statistics
+func mergeColumn(merged *pb.ColumnStatistics, partial *pb.ColumnStatistics, j int) {
+	merged.Missing[j] += partial.Missing[j]
+	b := partial.Weight[j]
+	if b <= 0 {
+		return
+	}
+	a := merged.Weight[j]
+	n := a + b
+	delta := partial.Mean[j] - merged.Mean[j]
+	m2a, m3a := merged.M2[j], merged.M3[j]
+	m2b, m3b := partial.M2[j], partial.M3[j]
+
+	merged.M4[j] += partial.M4[j] +
+		math.Pow(delta, 4)*a*b*(a*a-a*b+b*b)/(n*n*n) +
+		6*delta*delta*(a*a*m2b+b*b*m2a)/(n*n) +
+		4*delta*(a*m3b-b*m3a)/n
+	merged.M3[j] += m3b + math.Pow(delta, 3)*a*b*(a-b)/(n*n) + 3*delta*(a*m2b-b*m2a)/n
+	merged.M2[j] += m2b + delta*delta*a*b/n
+	merged.Mean[j] += delta * b / n
+	merged.Weight[j] = n
+	merged.Min[j] = math.Min(merged.Min[j], partial.Min[j])
+	merged.Max[j] = math.Max(merged.Max[j], partial.Max[j])
+}
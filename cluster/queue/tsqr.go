@@ -0,0 +1,101 @@
+package queue
+
+import (
+	"errors"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+// tsqrPCA computes the principal components from a tall-skinny QR
+// decomposition of the standardized data. Each worker returns the R factor of
+// its partition, the factors are reduced pairwise into the R factor of the
+// full dataset and the singular value decomposition of that factor gives the
+// components directly. Because the scatter matrix is never formed the
+// condition number is not squared, preserving the accuracy of small
+// components. The singular values are recorded in the response
+func tsqrPCA(job *Job, meanAndSD *pb.Matrix, cols int, resp *Response) (*matrix.DenseMatrix, []float64, error) {
+	err := standardize(job, meanAndSD)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	factors := make([]*matrix.DenseMatrix, job.Workers)
+	err = execute(job, &phase{
+		name:    "GetTriangularFactor",
+		failure: "Could not get triangular factor",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.GetTriangularFactor(context.Background(), &pb.Unit{})
+		},
+		combine: func(i int, result interface{}) error {
+			factors[i] = toDense(result.(*pb.Matrix))
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for len(factors) > 1 {
+		reduced := []*matrix.DenseMatrix{}
+		for i := 0; i < len(factors); i += 2 {
+			if i+1 == len(factors) {
+				reduced = append(reduced, factors[i])
+				continue
+			}
+			r, err := reduceFactors(factors[i], factors[i+1], cols)
+			if err != nil {
+				grpclog.Printf("Failed to reduce triangular factors: %v", err)
+				return nil, nil, errors.New("Could not reduce triangular factors")
+			}
+			reduced = append(reduced, r)
+		}
+		factors = reduced
+	}
+
+	r := factors[0]
+	if r.Rows() < cols {
+		r, err = r.Stack(matrix.Zeros(cols-r.Rows(), cols))
+		if err != nil {
+			grpclog.Printf("Failed to pad triangular factor: %v", err)
+			return nil, nil, errors.New("Could not reduce triangular factors")
+		}
+	}
+
+	_, singularValues, eigenvectors, err := linalg.SVD(r)
+	if err != nil {
+		grpclog.Printf("Failed to compute SVD(): %v", err)
+		return nil, nil, errors.New("Could not compute singular values/vectors")
+	}
+	resp.SingularValues = singularValues
+
+	eigenvalues := make([]float64, len(singularValues))
+	for i, value := range singularValues {
+		eigenvalues[i] = value * value
+	}
+
+	return eigenvectors, eigenvalues, nil
+}
+
+// reduceFactors stacks two R factors and returns the R factor of the stack.
+// Stacks with fewer rows than columns are returned as they are
+func reduceFactors(a *matrix.DenseMatrix, b *matrix.DenseMatrix, cols int) (*matrix.DenseMatrix, error) {
+	stacked, err := a.Stack(b)
+	if err != nil {
+		return nil, err
+	}
+	if stacked.Rows() < cols {
+		return stacked, nil
+	}
+
+	_, r, err := linalg.QR(stacked)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
@@ -0,0 +1,186 @@
+package queue
+
+import (
+	"errors"
+	"math"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/grpclog"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+// loaded holds the statistics of the dataset currently loaded on the workers
+// if it was last computed in exact mode
+var loaded *model
+
+// model is the sufficient statistics of a dataset: the number and total
+// weight of the rows, the weighted mean of each column and the weighted
+// scatter matrix of the rows about that mean. Only the scatter formulation
+// keeps the statistics, and robust scaling takes the scatter matrix about the
+// medians instead, so neither model can be updated
+type model struct {
+	dataset     string
+	workers     int
+	formulation string
+	scaling     string
+	rows        int
+	weight      float64
+	mean        []float64
+	scatter     *matrix.DenseMatrix
+}
+
+// newModel builds a model from the mean, standard deviation and standardized
+// scatter matrix of an exact job by undoing the scaling of the scatter matrix
+func newModel(job *Job, rows int, weight float64, meanAndSD *pb.Matrix, standardized *matrix.DenseMatrix) *model {
+	sd := meanAndSD.Elements[1].Elements
+	scatter := standardized.Copy()
+	for i := range sd {
+		for j := range sd {
+			scatter.Set(i, j, scatter.Get(i, j)*(sd[i]*sd[j]))
+		}
+	}
+
+	mean := make([]float64, len(sd))
+	copy(mean, meanAndSD.Elements[0].Elements)
+
+	scaling := ScalingSD
+	if job.Standardize && job.Scaling != "" {
+		scaling = job.Scaling
+	}
+
+	return &model{
+		dataset:     job.Dataset,
+		workers:     job.Workers,
+		formulation: FormulationScatter,
+		scaling:     scaling,
+		rows:        rows,
+		weight:      weight,
+		mean:        mean,
+		scatter:     scatter,
+	}
+}
+
+// merge combines the statistics of new rows into the model using the pairwise
+// update of Chan et al. The sum and scatter matrix of the new rows are
+// weighted and the scatter matrix is about their own weighted mean
+func (m *model) merge(moments *pb.Moments) error {
+	total := m.weight + moments.Weight
+	delta := make([]float64, len(m.mean))
+	for i := range delta {
+		delta[i] = moments.Sum.Elements[i]/moments.Weight - m.mean[i]
+	}
+
+	err := m.scatter.Add(toDense(moments.Scatter))
+	if err != nil {
+		return err
+	}
+	scale := m.weight * moments.Weight / total
+	for i := range delta {
+		for j := range delta {
+			m.scatter.Set(i, j, m.scatter.Get(i, j)+scale*delta[i]*delta[j])
+		}
+		m.mean[i] += delta[i] * moments.Weight / total
+	}
+	m.rows += int(moments.Rows)
+	m.weight = total
+
+	return nil
+}
+
+// updatePCA appends the job's new rows to the dataset already loaded on the
+// workers, merges their statistics into the model kept from the last exact
+// job and re-solves the eigenproblem. Only the new rows are read, so this is
+// much faster than recomputing the principal components from scratch. An
+// update scales the columns by their standard deviations and does not shrink
+// the covariance, whatever the shrinkage of the job that built the model
+func updatePCA(job *Job, resp *Response) (*matrix.DenseMatrix, []float64, float64, error) {
+	if loaded == nil || loaded.dataset != job.Dataset || loaded.workers != job.Workers {
+		grpclog.Printf("No model loaded for %s with %d workers", job.Dataset, job.Workers)
+		return nil, nil, 0, errors.New("No model to update. Run an exact job first.")
+	}
+	if loaded.formulation != FormulationScatter {
+		grpclog.Printf("Model of %s was solved with the %s formulation", job.Dataset, loaded.formulation)
+		return nil, nil, 0, errors.New("Cannot update a model solved with the Gram matrix")
+	}
+	if loaded.scaling == ScalingRobust {
+		grpclog.Printf("Model of %s was built with %s scaling", job.Dataset, loaded.scaling)
+		return nil, nil, 0, errors.New("Cannot update a model built with robust scaling")
+	}
+	cols := len(loaded.mean)
+
+	updates := make([]*pb.Moments, job.Workers)
+	err := execute(job, &phase{
+		name:    "AppendData",
+		failure: "Could not append data",
+		task: func(i int, client pb.WorkerClient) (interface{}, error) {
+			return client.AppendData(context.Background(), partition(job, job.Update, i))
+		},
+		combine: func(i int, result interface{}) error {
+			updates[i] = result.(*pb.Moments)
+			return nil
+		},
+	})
+	if err != nil {
+		loaded = nil
+		return nil, nil, 0, err
+	}
+
+	for _, update := range updates {
+		if len(update.Sum.Elements) != cols || len(update.Scatter.Elements) != cols || update.Weight <= 0 {
+			grpclog.Printf("Inconsistent vector sizes: %v, %v", len(update.Sum.Elements), cols)
+			loaded = nil
+			return nil, nil, 0, errors.New("Inconsistent vectors sizes")
+		}
+		err = loaded.merge(update)
+		if err != nil {
+			grpclog.Printf("Failed to merge moments: %v", err)
+			loaded = nil
+			return nil, nil, 0, errors.New("Could not merge appended data")
+		}
+	}
+	resp.Rows = loaded.rows
+	resp.TotalWeight = loaded.weight
+	resp.Formulation = FormulationScatter
+
+	sdArray := make([]float64, cols)
+	for i := range sdArray {
+		if job.Standardize {
+			sdArray[i] = math.Sqrt(loaded.scatter.Get(i, i) / loaded.weight)
+		} else {
+			sdArray[i] = 1
+		}
+	}
+
+	scatter := loaded.scatter.Copy()
+	var totalVariance float64
+	for i := range sdArray {
+		for j := range sdArray {
+			scatter.Set(i, j, scatter.Get(i, j)/(sdArray[i]*sdArray[j]))
+		}
+		totalVariance += scatter.Get(i, i)
+	}
+
+	eigenvectors, eigenvalues, err := linalg.SymmetricEigen(scatter)
+	if err != nil {
+		grpclog.Printf("Failed to compute SymmetricEigen(): %v", err)
+		return nil, nil, 0, errors.New("Could not compute eigenvalues/vectors")
+	}
+
+	if save {
+		meanAndSD := &pb.Matrix{
+			Elements: []*pb.Vector{
+				&pb.Vector{Elements: loaded.mean},
+				&pb.Vector{Elements: sdArray},
+			},
+		}
+		err = standardize(job, meanAndSD)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	return eigenvectors, eigenvalues, totalVariance, nil
+}
@@ -14,6 +14,27 @@ It has these top-level messages:
 	Size
 	Vector
 	Matrix
+	Components
+	Moments
+	RobustEstimate
+	LatentModel
+	LatentStatistics
+	Permutation
+	Resample
+	Fold
+	Reconstruction
+	Clustering
+	ClusterSums
+	LinearModel
+	ResidualSummary
+	ClassMoments
+	ColumnGroups
+	IndependentModel
+	IndependentStatistics
+	ColumnStatistics
+	Binning
+	Digest
+	ColumnSketches
 */
 package rannu
 
@@ -46,7 +67,9 @@ func (*Unit) ProtoMessage()               {}
 func (*Unit) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
 
 type DataFile struct {
-	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Name         string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Weighted     bool   `protobuf:"varint,2,opt,name=weighted" json:"weighted,omitempty"`
+	WeightColumn int32  `protobuf:"varint,3,opt,name=weight_column,json=weightColumn" json:"weight_column,omitempty"`
 }
 
 func (m *DataFile) Reset()                    { *m = DataFile{} }
@@ -55,8 +78,10 @@ func (*DataFile) ProtoMessage()               {}
 func (*DataFile) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
 
 type Size struct {
-	Rows int32 `protobuf:"varint,1,opt,name=rows" json:"rows,omitempty"`
-	Cols int32 `protobuf:"varint,2,opt,name=cols" json:"cols,omitempty"`
+	Rows    int32   `protobuf:"varint,1,opt,name=rows" json:"rows,omitempty"`
+	Cols    int32   `protobuf:"varint,2,opt,name=cols" json:"cols,omitempty"`
+	Weight  float64 `protobuf:"fixed64,3,opt,name=weight" json:"weight,omitempty"`
+	Missing int64   `protobuf:"varint,4,opt,name=missing" json:"missing,omitempty"`
 }
 
 func (m *Size) Reset()                    { *m = Size{} }
@@ -89,12 +114,434 @@ func (m *Matrix) GetElements() []*Vector {
 	return nil
 }
 
+type Components struct {
+	K int32 `protobuf:"varint,1,opt,name=k" json:"k,omitempty"`
+}
+
+func (m *Components) Reset()                    { *m = Components{} }
+func (m *Components) String() string            { return proto.CompactTextString(m) }
+func (*Components) ProtoMessage()               {}
+func (*Components) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+type Moments struct {
+	Rows    int32   `protobuf:"varint,1,opt,name=rows" json:"rows,omitempty"`
+	Sum     *Vector `protobuf:"bytes,2,opt,name=sum" json:"sum,omitempty"`
+	Scatter *Matrix `protobuf:"bytes,3,opt,name=scatter" json:"scatter,omitempty"`
+	Weight  float64 `protobuf:"fixed64,4,opt,name=weight" json:"weight,omitempty"`
+}
+
+func (m *Moments) Reset()                    { *m = Moments{} }
+func (m *Moments) String() string            { return proto.CompactTextString(m) }
+func (*Moments) ProtoMessage()               {}
+func (*Moments) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *Moments) GetSum() *Vector {
+	if m != nil {
+		return m.Sum
+	}
+	return nil
+}
+
+func (m *Moments) GetScatter() *Matrix {
+	if m != nil {
+		return m.Scatter
+	}
+	return nil
+}
+
+type RobustEstimate struct {
+	Center    *Vector `protobuf:"bytes,1,opt,name=center" json:"center,omitempty"`
+	Precision *Matrix `protobuf:"bytes,2,opt,name=precision" json:"precision,omitempty"`
+	Cutoff    float64 `protobuf:"fixed64,3,opt,name=cutoff" json:"cutoff,omitempty"`
+}
+
+func (m *RobustEstimate) Reset()                    { *m = RobustEstimate{} }
+func (m *RobustEstimate) String() string            { return proto.CompactTextString(m) }
+func (*RobustEstimate) ProtoMessage()               {}
+func (*RobustEstimate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *RobustEstimate) GetCenter() *Vector {
+	if m != nil {
+		return m.Center
+	}
+	return nil
+}
+
+func (m *RobustEstimate) GetPrecision() *Matrix {
+	if m != nil {
+		return m.Precision
+	}
+	return nil
+}
+
+type LatentModel struct {
+	Loadings *Matrix `protobuf:"bytes,1,opt,name=loadings" json:"loadings,omitempty"`
+	Noise    float64 `protobuf:"fixed64,2,opt,name=noise" json:"noise,omitempty"`
+}
+
+func (m *LatentModel) Reset()                    { *m = LatentModel{} }
+func (m *LatentModel) String() string            { return proto.CompactTextString(m) }
+func (*LatentModel) ProtoMessage()               {}
+func (*LatentModel) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *LatentModel) GetLoadings() *Matrix {
+	if m != nil {
+		return m.Loadings
+	}
+	return nil
+}
+
+type LatentStatistics struct {
+	Products      *Matrix `protobuf:"bytes,1,opt,name=products" json:"products,omitempty"`
+	Moments       *Matrix `protobuf:"bytes,2,opt,name=moments" json:"moments,omitempty"`
+	Squares       float64 `protobuf:"fixed64,3,opt,name=squares" json:"squares,omitempty"`
+	Observed      float64 `protobuf:"fixed64,4,opt,name=observed" json:"observed,omitempty"`
+	LogLikelihood float64 `protobuf:"fixed64,5,opt,name=log_likelihood,json=logLikelihood" json:"log_likelihood,omitempty"`
+}
+
+func (m *LatentStatistics) Reset()                    { *m = LatentStatistics{} }
+func (m *LatentStatistics) String() string            { return proto.CompactTextString(m) }
+func (*LatentStatistics) ProtoMessage()               {}
+func (*LatentStatistics) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *LatentStatistics) GetProducts() *Matrix {
+	if m != nil {
+		return m.Products
+	}
+	return nil
+}
+
+func (m *LatentStatistics) GetMoments() *Matrix {
+	if m != nil {
+		return m.Moments
+	}
+	return nil
+}
+
+type Permutation struct {
+	Seed int64 `protobuf:"varint,1,opt,name=seed" json:"seed,omitempty"`
+}
+
+func (m *Permutation) Reset()                    { *m = Permutation{} }
+func (m *Permutation) String() string            { return proto.CompactTextString(m) }
+func (*Permutation) ProtoMessage()               {}
+func (*Permutation) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+type Resample struct {
+	Seed int64 `protobuf:"varint,1,opt,name=seed" json:"seed,omitempty"`
+}
+
+func (m *Resample) Reset()                    { *m = Resample{} }
+func (m *Resample) String() string            { return proto.CompactTextString(m) }
+func (*Resample) ProtoMessage()               {}
+func (*Resample) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+type Fold struct {
+	Index int32 `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
+	Count int32 `protobuf:"varint,2,opt,name=count" json:"count,omitempty"`
+}
+
+func (m *Fold) Reset()                    { *m = Fold{} }
+func (m *Fold) String() string            { return proto.CompactTextString(m) }
+func (*Fold) ProtoMessage()               {}
+func (*Fold) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+type Reconstruction struct {
+	Fold       *Fold   `protobuf:"bytes,1,opt,name=fold" json:"fold,omitempty"`
+	Center     *Vector `protobuf:"bytes,2,opt,name=center" json:"center,omitempty"`
+	Components *Matrix `protobuf:"bytes,3,opt,name=components" json:"components,omitempty"`
+}
+
+func (m *Reconstruction) Reset()                    { *m = Reconstruction{} }
+func (m *Reconstruction) String() string            { return proto.CompactTextString(m) }
+func (*Reconstruction) ProtoMessage()               {}
+func (*Reconstruction) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *Reconstruction) GetFold() *Fold {
+	if m != nil {
+		return m.Fold
+	}
+	return nil
+}
+
+func (m *Reconstruction) GetCenter() *Vector {
+	if m != nil {
+		return m.Center
+	}
+	return nil
+}
+
+func (m *Reconstruction) GetComponents() *Matrix {
+	if m != nil {
+		return m.Components
+	}
+	return nil
+}
+
+type Clustering struct {
+	Centroids  *Matrix `protobuf:"bytes,1,opt,name=centroids" json:"centroids,omitempty"`
+	Projection *Matrix `protobuf:"bytes,2,opt,name=projection" json:"projection,omitempty"`
+	Factor     float64 `protobuf:"fixed64,3,opt,name=factor" json:"factor,omitempty"`
+	Seed       int64   `protobuf:"varint,4,opt,name=seed" json:"seed,omitempty"`
+}
+
+func (m *Clustering) Reset()                    { *m = Clustering{} }
+func (m *Clustering) String() string            { return proto.CompactTextString(m) }
+func (*Clustering) ProtoMessage()               {}
+func (*Clustering) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *Clustering) GetCentroids() *Matrix {
+	if m != nil {
+		return m.Centroids
+	}
+	return nil
+}
+
+func (m *Clustering) GetProjection() *Matrix {
+	if m != nil {
+		return m.Projection
+	}
+	return nil
+}
+
+type ClusterSums struct {
+	Sums    *Matrix `protobuf:"bytes,1,opt,name=sums" json:"sums,omitempty"`
+	Weights *Vector `protobuf:"bytes,2,opt,name=weights" json:"weights,omitempty"`
+	Cost    float64 `protobuf:"fixed64,3,opt,name=cost" json:"cost,omitempty"`
+}
+
+func (m *ClusterSums) Reset()                    { *m = ClusterSums{} }
+func (m *ClusterSums) String() string            { return proto.CompactTextString(m) }
+func (*ClusterSums) ProtoMessage()               {}
+func (*ClusterSums) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *ClusterSums) GetSums() *Matrix {
+	if m != nil {
+		return m.Sums
+	}
+	return nil
+}
+
+func (m *ClusterSums) GetWeights() *Vector {
+	if m != nil {
+		return m.Weights
+	}
+	return nil
+}
+
+type LinearModel struct {
+	Target       int32   `protobuf:"varint,1,opt,name=target" json:"target,omitempty"`
+	Coefficients *Vector `protobuf:"bytes,2,opt,name=coefficients" json:"coefficients,omitempty"`
+	Intercept    float64 `protobuf:"fixed64,3,opt,name=intercept" json:"intercept,omitempty"`
+}
+
+func (m *LinearModel) Reset()                    { *m = LinearModel{} }
+func (m *LinearModel) String() string            { return proto.CompactTextString(m) }
+func (*LinearModel) ProtoMessage()               {}
+func (*LinearModel) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *LinearModel) GetCoefficients() *Vector {
+	if m != nil {
+		return m.Coefficients
+	}
+	return nil
+}
+
+type ResidualSummary struct {
+	Weight   float64 `protobuf:"fixed64,1,opt,name=weight" json:"weight,omitempty"`
+	Sum      float64 `protobuf:"fixed64,2,opt,name=sum" json:"sum,omitempty"`
+	Squares  float64 `protobuf:"fixed64,3,opt,name=squares" json:"squares,omitempty"`
+	Absolute float64 `protobuf:"fixed64,4,opt,name=absolute" json:"absolute,omitempty"`
+	Min      float64 `protobuf:"fixed64,5,opt,name=min" json:"min,omitempty"`
+	Max      float64 `protobuf:"fixed64,6,opt,name=max" json:"max,omitempty"`
+}
+
+func (m *ResidualSummary) Reset()                    { *m = ResidualSummary{} }
+func (m *ResidualSummary) String() string            { return proto.CompactTextString(m) }
+func (*ResidualSummary) ProtoMessage()               {}
+func (*ResidualSummary) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+type ClassMoments struct {
+	Labels  []float64  `protobuf:"fixed64,1,rep,packed,name=labels" json:"labels,omitempty"`
+	Moments []*Moments `protobuf:"bytes,2,rep,name=moments" json:"moments,omitempty"`
+}
+
+func (m *ClassMoments) Reset()                    { *m = ClassMoments{} }
+func (m *ClassMoments) String() string            { return proto.CompactTextString(m) }
+func (*ClassMoments) ProtoMessage()               {}
+func (*ClassMoments) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *ClassMoments) GetMoments() []*Moments {
+	if m != nil {
+		return m.Moments
+	}
+	return nil
+}
+
+type ColumnGroups struct {
+	First  []int32 `protobuf:"varint,1,rep,packed,name=first" json:"first,omitempty"`
+	Second []int32 `protobuf:"varint,2,rep,packed,name=second" json:"second,omitempty"`
+}
+
+func (m *ColumnGroups) Reset()                    { *m = ColumnGroups{} }
+func (m *ColumnGroups) String() string            { return proto.CompactTextString(m) }
+func (*ColumnGroups) ProtoMessage()               {}
+func (*ColumnGroups) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+type IndependentModel struct {
+	Whitening *Matrix `protobuf:"bytes,1,opt,name=whitening" json:"whitening,omitempty"`
+	Unmixing  *Matrix `protobuf:"bytes,2,opt,name=unmixing" json:"unmixing,omitempty"`
+}
+
+func (m *IndependentModel) Reset()                    { *m = IndependentModel{} }
+func (m *IndependentModel) String() string            { return proto.CompactTextString(m) }
+func (*IndependentModel) ProtoMessage()               {}
+func (*IndependentModel) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *IndependentModel) GetWhitening() *Matrix {
+	if m != nil {
+		return m.Whitening
+	}
+	return nil
+}
+
+func (m *IndependentModel) GetUnmixing() *Matrix {
+	if m != nil {
+		return m.Unmixing
+	}
+	return nil
+}
+
+type IndependentStatistics struct {
+	Products    *Matrix `protobuf:"bytes,1,opt,name=products" json:"products,omitempty"`
+	Derivatives *Vector `protobuf:"bytes,2,opt,name=derivatives" json:"derivatives,omitempty"`
+	Weight      float64 `protobuf:"fixed64,3,opt,name=weight" json:"weight,omitempty"`
+}
+
+func (m *IndependentStatistics) Reset()                    { *m = IndependentStatistics{} }
+func (m *IndependentStatistics) String() string            { return proto.CompactTextString(m) }
+func (*IndependentStatistics) ProtoMessage()               {}
+func (*IndependentStatistics) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *IndependentStatistics) GetProducts() *Matrix {
+	if m != nil {
+		return m.Products
+	}
+	return nil
+}
+
+func (m *IndependentStatistics) GetDerivatives() *Vector {
+	if m != nil {
+		return m.Derivatives
+	}
+	return nil
+}
+
+type ColumnStatistics struct {
+	Weight  []float64 `protobuf:"fixed64,1,rep,packed,name=weight" json:"weight,omitempty"`
+	Missing []int64   `protobuf:"varint,2,rep,packed,name=missing" json:"missing,omitempty"`
+	Min     []float64 `protobuf:"fixed64,3,rep,packed,name=min" json:"min,omitempty"`
+	Max     []float64 `protobuf:"fixed64,4,rep,packed,name=max" json:"max,omitempty"`
+	Mean    []float64 `protobuf:"fixed64,5,rep,packed,name=mean" json:"mean,omitempty"`
+	M2      []float64 `protobuf:"fixed64,6,rep,packed,name=m2" json:"m2,omitempty"`
+	M3      []float64 `protobuf:"fixed64,7,rep,packed,name=m3" json:"m3,omitempty"`
+	M4      []float64 `protobuf:"fixed64,8,rep,packed,name=m4" json:"m4,omitempty"`
+}
+
+func (m *ColumnStatistics) Reset()                    { *m = ColumnStatistics{} }
+func (m *ColumnStatistics) String() string            { return proto.CompactTextString(m) }
+func (*ColumnStatistics) ProtoMessage()               {}
+func (*ColumnStatistics) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+type Binning struct {
+	Bins        int32   `protobuf:"varint,1,opt,name=bins" json:"bins,omitempty"`
+	Min         *Vector `protobuf:"bytes,2,opt,name=min" json:"min,omitempty"`
+	Max         *Vector `protobuf:"bytes,3,opt,name=max" json:"max,omitempty"`
+	Compression float64 `protobuf:"fixed64,4,opt,name=compression" json:"compression,omitempty"`
+}
+
+func (m *Binning) Reset()                    { *m = Binning{} }
+func (m *Binning) String() string            { return proto.CompactTextString(m) }
+func (*Binning) ProtoMessage()               {}
+func (*Binning) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *Binning) GetMin() *Vector {
+	if m != nil {
+		return m.Min
+	}
+	return nil
+}
+
+func (m *Binning) GetMax() *Vector {
+	if m != nil {
+		return m.Max
+	}
+	return nil
+}
+
+type Digest struct {
+	Means   []float64 `protobuf:"fixed64,1,rep,packed,name=means" json:"means,omitempty"`
+	Weights []float64 `protobuf:"fixed64,2,rep,packed,name=weights" json:"weights,omitempty"`
+	Min     float64   `protobuf:"fixed64,3,opt,name=min" json:"min,omitempty"`
+	Max     float64   `protobuf:"fixed64,4,opt,name=max" json:"max,omitempty"`
+}
+
+func (m *Digest) Reset()                    { *m = Digest{} }
+func (m *Digest) String() string            { return proto.CompactTextString(m) }
+func (*Digest) ProtoMessage()               {}
+func (*Digest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+type ColumnSketches struct {
+	Digests    []*Digest `protobuf:"bytes,1,rep,name=digests" json:"digests,omitempty"`
+	Histograms *Matrix   `protobuf:"bytes,2,opt,name=histograms" json:"histograms,omitempty"`
+}
+
+func (m *ColumnSketches) Reset()                    { *m = ColumnSketches{} }
+func (m *ColumnSketches) String() string            { return proto.CompactTextString(m) }
+func (*ColumnSketches) ProtoMessage()               {}
+func (*ColumnSketches) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *ColumnSketches) GetDigests() []*Digest {
+	if m != nil {
+		return m.Digests
+	}
+	return nil
+}
+
+func (m *ColumnSketches) GetHistograms() *Matrix {
+	if m != nil {
+		return m.Histograms
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Unit)(nil), "rannu.Unit")
 	proto.RegisterType((*DataFile)(nil), "rannu.DataFile")
 	proto.RegisterType((*Size)(nil), "rannu.Size")
 	proto.RegisterType((*Vector)(nil), "rannu.Vector")
 	proto.RegisterType((*Matrix)(nil), "rannu.Matrix")
+	proto.RegisterType((*Components)(nil), "rannu.Components")
+	proto.RegisterType((*Moments)(nil), "rannu.Moments")
+	proto.RegisterType((*RobustEstimate)(nil), "rannu.RobustEstimate")
+	proto.RegisterType((*LatentModel)(nil), "rannu.LatentModel")
+	proto.RegisterType((*LatentStatistics)(nil), "rannu.LatentStatistics")
+	proto.RegisterType((*Permutation)(nil), "rannu.Permutation")
+	proto.RegisterType((*Resample)(nil), "rannu.Resample")
+	proto.RegisterType((*Fold)(nil), "rannu.Fold")
+	proto.RegisterType((*Reconstruction)(nil), "rannu.Reconstruction")
+	proto.RegisterType((*Clustering)(nil), "rannu.Clustering")
+	proto.RegisterType((*ClusterSums)(nil), "rannu.ClusterSums")
+	proto.RegisterType((*LinearModel)(nil), "rannu.LinearModel")
+	proto.RegisterType((*ResidualSummary)(nil), "rannu.ResidualSummary")
+	proto.RegisterType((*ClassMoments)(nil), "rannu.ClassMoments")
+	proto.RegisterType((*ColumnGroups)(nil), "rannu.ColumnGroups")
+	proto.RegisterType((*IndependentModel)(nil), "rannu.IndependentModel")
+	proto.RegisterType((*IndependentStatistics)(nil), "rannu.IndependentStatistics")
+	proto.RegisterType((*ColumnStatistics)(nil), "rannu.ColumnStatistics")
+	proto.RegisterType((*Binning)(nil), "rannu.Binning")
+	proto.RegisterType((*Digest)(nil), "rannu.Digest")
+	proto.RegisterType((*ColumnSketches)(nil), "rannu.ColumnSketches")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -105,169 +552,894 @@ var _ grpc.ClientConn
 // is compatible with the grpc package it is being compiled against.
 const _ = grpc.SupportPackageIsVersion3
 
-// Client API for Worker service
+// Client API for Worker service
+
+type WorkerClient interface {
+	LoadData(ctx context.Context, in *DataFile, opts ...grpc.CallOption) (*Size, error)
+	GetSum(ctx context.Context, in *Unit, opts ...grpc.CallOption) (*Vector, error)
+	GetVariance(ctx context.Context, in *Vector, opts ...grpc.CallOption) (*Vector, error)
+	GetScatterMatrix(ctx context.Context, in *Matrix, opts ...grpc.CallOption) (*Matrix, error)
+	ComputeScores(ctx context.Context, in *Matrix, opts ...grpc.CallOption) (*DataFile, error)
+	Standardize(ctx context.Context, in *Matrix, opts ...grpc.CallOption) (*Unit, error)
+	GetRangeSketch(ctx context.Context, in *Matrix, opts ...grpc.CallOption) (*Matrix, error)
+	GetProjectedScatter(ctx context.Context, in *Matrix, opts ...grpc.CallOption) (*Matrix, error)
+	GetTriangularFactor(ctx context.Context, in *Unit, opts ...grpc.CallOption) (*Matrix, error)
+	GetLocalComponents(ctx context.Context, in *Components, opts ...grpc.CallOption) (*Matrix, error)
+	GetRowBlock(ctx context.Context, in *Unit, opts ...grpc.CallOption) (*Matrix, error)
+	AppendData(ctx context.Context, in *DataFile, opts ...grpc.CallOption) (*Moments, error)
+	GetRobustMoments(ctx context.Context, in *RobustEstimate, opts ...grpc.CallOption) (*Moments, error)
+	GetRobustWeights(ctx context.Context, in *Unit, opts ...grpc.CallOption) (*Vector, error)
+	GetObservedMoments(ctx context.Context, in *Unit, opts ...grpc.CallOption) (*Matrix, error)
+	GetLatentStatistics(ctx context.Context, in *LatentModel, opts ...grpc.CallOption) (*LatentStatistics, error)
+	GetPermutedScatter(ctx context.Context, in *Permutation, opts ...grpc.CallOption) (*Matrix, error)
+	GetBootstrapMoments(ctx context.Context, in *Resample, opts ...grpc.CallOption) (*Moments, error)
+	GetTrainingMoments(ctx context.Context, in *Fold, opts ...grpc.CallOption) (*Moments, error)
+	GetReconstructionError(ctx context.Context, in *Reconstruction, opts ...grpc.CallOption) (*Vector, error)
+	SampleCentroids(ctx context.Context, in *Clustering, opts ...grpc.CallOption) (*Matrix, error)
+	AssignClusters(ctx context.Context, in *Clustering, opts ...grpc.CallOption) (*ClusterSums, error)
+	GetResiduals(ctx context.Context, in *LinearModel, opts ...grpc.CallOption) (*ResidualSummary, error)
+	GetClassMoments(ctx context.Context, in *Unit, opts ...grpc.CallOption) (*ClassMoments, error)
+	GetLabeledScores(ctx context.Context, in *Matrix, opts ...grpc.CallOption) (*Matrix, error)
+	GetGroupScatter(ctx context.Context, in *ColumnGroups, opts ...grpc.CallOption) (*Matrix, error)
+	GetIndependentStatistics(ctx context.Context, in *IndependentModel, opts ...grpc.CallOption) (*IndependentStatistics, error)
+	GetColumnStatistics(ctx context.Context, in *Unit, opts ...grpc.CallOption) (*ColumnStatistics, error)
+	GetColumnSketches(ctx context.Context, in *Binning, opts ...grpc.CallOption) (*ColumnSketches, error)
+	GetFourthMoment(ctx context.Context, in *Unit, opts ...grpc.CallOption) (*Vector, error)
+}
+
+type workerClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewWorkerClient(cc *grpc.ClientConn) WorkerClient {
+	return &workerClient{cc}
+}
+
+func (c *workerClient) LoadData(ctx context.Context, in *DataFile, opts ...grpc.CallOption) (*Size, error) {
+	out := new(Size)
+	err := grpc.Invoke(ctx, "/rannu.Worker/LoadData", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetSum(ctx context.Context, in *Unit, opts ...grpc.CallOption) (*Vector, error) {
+	out := new(Vector)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetSum", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetVariance(ctx context.Context, in *Vector, opts ...grpc.CallOption) (*Vector, error) {
+	out := new(Vector)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetVariance", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetScatterMatrix(ctx context.Context, in *Matrix, opts ...grpc.CallOption) (*Matrix, error) {
+	out := new(Matrix)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetScatterMatrix", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) ComputeScores(ctx context.Context, in *Matrix, opts ...grpc.CallOption) (*DataFile, error) {
+	out := new(DataFile)
+	err := grpc.Invoke(ctx, "/rannu.Worker/ComputeScores", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) Standardize(ctx context.Context, in *Matrix, opts ...grpc.CallOption) (*Unit, error) {
+	out := new(Unit)
+	err := grpc.Invoke(ctx, "/rannu.Worker/Standardize", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetRangeSketch(ctx context.Context, in *Matrix, opts ...grpc.CallOption) (*Matrix, error) {
+	out := new(Matrix)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetRangeSketch", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetProjectedScatter(ctx context.Context, in *Matrix, opts ...grpc.CallOption) (*Matrix, error) {
+	out := new(Matrix)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetProjectedScatter", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetTriangularFactor(ctx context.Context, in *Unit, opts ...grpc.CallOption) (*Matrix, error) {
+	out := new(Matrix)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetTriangularFactor", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetLocalComponents(ctx context.Context, in *Components, opts ...grpc.CallOption) (*Matrix, error) {
+	out := new(Matrix)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetLocalComponents", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetRowBlock(ctx context.Context, in *Unit, opts ...grpc.CallOption) (*Matrix, error) {
+	out := new(Matrix)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetRowBlock", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) AppendData(ctx context.Context, in *DataFile, opts ...grpc.CallOption) (*Moments, error) {
+	out := new(Moments)
+	err := grpc.Invoke(ctx, "/rannu.Worker/AppendData", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetRobustMoments(ctx context.Context, in *RobustEstimate, opts ...grpc.CallOption) (*Moments, error) {
+	out := new(Moments)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetRobustMoments", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetRobustWeights(ctx context.Context, in *Unit, opts ...grpc.CallOption) (*Vector, error) {
+	out := new(Vector)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetRobustWeights", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetObservedMoments(ctx context.Context, in *Unit, opts ...grpc.CallOption) (*Matrix, error) {
+	out := new(Matrix)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetObservedMoments", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetLatentStatistics(ctx context.Context, in *LatentModel, opts ...grpc.CallOption) (*LatentStatistics, error) {
+	out := new(LatentStatistics)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetLatentStatistics", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetPermutedScatter(ctx context.Context, in *Permutation, opts ...grpc.CallOption) (*Matrix, error) {
+	out := new(Matrix)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetPermutedScatter", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetBootstrapMoments(ctx context.Context, in *Resample, opts ...grpc.CallOption) (*Moments, error) {
+	out := new(Moments)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetBootstrapMoments", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetTrainingMoments(ctx context.Context, in *Fold, opts ...grpc.CallOption) (*Moments, error) {
+	out := new(Moments)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetTrainingMoments", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetReconstructionError(ctx context.Context, in *Reconstruction, opts ...grpc.CallOption) (*Vector, error) {
+	out := new(Vector)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetReconstructionError", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) SampleCentroids(ctx context.Context, in *Clustering, opts ...grpc.CallOption) (*Matrix, error) {
+	out := new(Matrix)
+	err := grpc.Invoke(ctx, "/rannu.Worker/SampleCentroids", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) AssignClusters(ctx context.Context, in *Clustering, opts ...grpc.CallOption) (*ClusterSums, error) {
+	out := new(ClusterSums)
+	err := grpc.Invoke(ctx, "/rannu.Worker/AssignClusters", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetResiduals(ctx context.Context, in *LinearModel, opts ...grpc.CallOption) (*ResidualSummary, error) {
+	out := new(ResidualSummary)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetResiduals", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetClassMoments(ctx context.Context, in *Unit, opts ...grpc.CallOption) (*ClassMoments, error) {
+	out := new(ClassMoments)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetClassMoments", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetLabeledScores(ctx context.Context, in *Matrix, opts ...grpc.CallOption) (*Matrix, error) {
+	out := new(Matrix)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetLabeledScores", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetGroupScatter(ctx context.Context, in *ColumnGroups, opts ...grpc.CallOption) (*Matrix, error) {
+	out := new(Matrix)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetGroupScatter", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetIndependentStatistics(ctx context.Context, in *IndependentModel, opts ...grpc.CallOption) (*IndependentStatistics, error) {
+	out := new(IndependentStatistics)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetIndependentStatistics", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetColumnStatistics(ctx context.Context, in *Unit, opts ...grpc.CallOption) (*ColumnStatistics, error) {
+	out := new(ColumnStatistics)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetColumnStatistics", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetColumnSketches(ctx context.Context, in *Binning, opts ...grpc.CallOption) (*ColumnSketches, error) {
+	out := new(ColumnSketches)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetColumnSketches", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) GetFourthMoment(ctx context.Context, in *Unit, opts ...grpc.CallOption) (*Vector, error) {
+	out := new(Vector)
+	err := grpc.Invoke(ctx, "/rannu.Worker/GetFourthMoment", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Worker service
+
+type WorkerServer interface {
+	LoadData(context.Context, *DataFile) (*Size, error)
+	GetSum(context.Context, *Unit) (*Vector, error)
+	GetVariance(context.Context, *Vector) (*Vector, error)
+	GetScatterMatrix(context.Context, *Matrix) (*Matrix, error)
+	ComputeScores(context.Context, *Matrix) (*DataFile, error)
+	Standardize(context.Context, *Matrix) (*Unit, error)
+	GetRangeSketch(context.Context, *Matrix) (*Matrix, error)
+	GetProjectedScatter(context.Context, *Matrix) (*Matrix, error)
+	GetTriangularFactor(context.Context, *Unit) (*Matrix, error)
+	GetLocalComponents(context.Context, *Components) (*Matrix, error)
+	GetRowBlock(context.Context, *Unit) (*Matrix, error)
+	AppendData(context.Context, *DataFile) (*Moments, error)
+	GetRobustMoments(context.Context, *RobustEstimate) (*Moments, error)
+	GetRobustWeights(context.Context, *Unit) (*Vector, error)
+	GetObservedMoments(context.Context, *Unit) (*Matrix, error)
+	GetLatentStatistics(context.Context, *LatentModel) (*LatentStatistics, error)
+	GetPermutedScatter(context.Context, *Permutation) (*Matrix, error)
+	GetBootstrapMoments(context.Context, *Resample) (*Moments, error)
+	GetTrainingMoments(context.Context, *Fold) (*Moments, error)
+	GetReconstructionError(context.Context, *Reconstruction) (*Vector, error)
+	SampleCentroids(context.Context, *Clustering) (*Matrix, error)
+	AssignClusters(context.Context, *Clustering) (*ClusterSums, error)
+	GetResiduals(context.Context, *LinearModel) (*ResidualSummary, error)
+	GetClassMoments(context.Context, *Unit) (*ClassMoments, error)
+	GetLabeledScores(context.Context, *Matrix) (*Matrix, error)
+	GetGroupScatter(context.Context, *ColumnGroups) (*Matrix, error)
+	GetIndependentStatistics(context.Context, *IndependentModel) (*IndependentStatistics, error)
+	GetColumnStatistics(context.Context, *Unit) (*ColumnStatistics, error)
+	GetColumnSketches(context.Context, *Binning) (*ColumnSketches, error)
+	GetFourthMoment(context.Context, *Unit) (*Vector, error)
+}
+
+func RegisterWorkerServer(s *grpc.Server, srv WorkerServer) {
+	s.RegisterService(&_Worker_serviceDesc, srv)
+}
+
+func _Worker_LoadData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DataFile)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).LoadData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/LoadData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).LoadData(ctx, req.(*DataFile))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_GetSum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Unit)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).GetSum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetSum",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetSum(ctx, req.(*Unit))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_GetVariance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Vector)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).GetVariance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetVariance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetVariance(ctx, req.(*Vector))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_GetScatterMatrix_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Matrix)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).GetScatterMatrix(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetScatterMatrix",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetScatterMatrix(ctx, req.(*Matrix))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_ComputeScores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Matrix)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).ComputeScores(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/ComputeScores",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).ComputeScores(ctx, req.(*Matrix))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_Standardize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Matrix)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).Standardize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/Standardize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).Standardize(ctx, req.(*Matrix))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_GetRangeSketch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Matrix)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).GetRangeSketch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetRangeSketch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetRangeSketch(ctx, req.(*Matrix))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_GetProjectedScatter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Matrix)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).GetProjectedScatter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetProjectedScatter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetProjectedScatter(ctx, req.(*Matrix))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_GetTriangularFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Unit)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).GetTriangularFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetTriangularFactor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetTriangularFactor(ctx, req.(*Unit))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_GetLocalComponents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Components)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).GetLocalComponents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetLocalComponents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetLocalComponents(ctx, req.(*Components))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_GetRowBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Unit)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).GetRowBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetRowBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetRowBlock(ctx, req.(*Unit))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_AppendData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DataFile)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).AppendData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/AppendData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).AppendData(ctx, req.(*DataFile))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_GetRobustMoments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RobustEstimate)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).GetRobustMoments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetRobustMoments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetRobustMoments(ctx, req.(*RobustEstimate))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_GetRobustWeights_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Unit)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).GetRobustWeights(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetRobustWeights",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetRobustWeights(ctx, req.(*Unit))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-type WorkerClient interface {
-	LoadData(ctx context.Context, in *DataFile, opts ...grpc.CallOption) (*Size, error)
-	GetSum(ctx context.Context, in *Unit, opts ...grpc.CallOption) (*Vector, error)
-	GetVariance(ctx context.Context, in *Vector, opts ...grpc.CallOption) (*Vector, error)
-	GetScatterMatrix(ctx context.Context, in *Matrix, opts ...grpc.CallOption) (*Matrix, error)
-	ComputeScores(ctx context.Context, in *Matrix, opts ...grpc.CallOption) (*DataFile, error)
+func _Worker_GetObservedMoments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Unit)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).GetObservedMoments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetObservedMoments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetObservedMoments(ctx, req.(*Unit))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type workerClient struct {
-	cc *grpc.ClientConn
+func _Worker_GetLatentStatistics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LatentModel)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).GetLatentStatistics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetLatentStatistics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetLatentStatistics(ctx, req.(*LatentModel))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func NewWorkerClient(cc *grpc.ClientConn) WorkerClient {
-	return &workerClient{cc}
+func _Worker_GetPermutedScatter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Permutation)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).GetPermutedScatter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetPermutedScatter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetPermutedScatter(ctx, req.(*Permutation))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *workerClient) LoadData(ctx context.Context, in *DataFile, opts ...grpc.CallOption) (*Size, error) {
-	out := new(Size)
-	err := grpc.Invoke(ctx, "/rannu.Worker/LoadData", in, out, c.cc, opts...)
-	if err != nil {
+func _Worker_GetBootstrapMoments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Resample)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(WorkerServer).GetBootstrapMoments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetBootstrapMoments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetBootstrapMoments(ctx, req.(*Resample))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *workerClient) GetSum(ctx context.Context, in *Unit, opts ...grpc.CallOption) (*Vector, error) {
-	out := new(Vector)
-	err := grpc.Invoke(ctx, "/rannu.Worker/GetSum", in, out, c.cc, opts...)
-	if err != nil {
+func _Worker_GetTrainingMoments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Fold)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(WorkerServer).GetTrainingMoments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetTrainingMoments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetTrainingMoments(ctx, req.(*Fold))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *workerClient) GetVariance(ctx context.Context, in *Vector, opts ...grpc.CallOption) (*Vector, error) {
-	out := new(Vector)
-	err := grpc.Invoke(ctx, "/rannu.Worker/GetVariance", in, out, c.cc, opts...)
-	if err != nil {
+func _Worker_GetReconstructionError_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Reconstruction)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(WorkerServer).GetReconstructionError(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetReconstructionError",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetReconstructionError(ctx, req.(*Reconstruction))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *workerClient) GetScatterMatrix(ctx context.Context, in *Matrix, opts ...grpc.CallOption) (*Matrix, error) {
-	out := new(Matrix)
-	err := grpc.Invoke(ctx, "/rannu.Worker/GetScatterMatrix", in, out, c.cc, opts...)
-	if err != nil {
+func _Worker_SampleCentroids_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Clustering)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(WorkerServer).SampleCentroids(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/SampleCentroids",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).SampleCentroids(ctx, req.(*Clustering))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *workerClient) ComputeScores(ctx context.Context, in *Matrix, opts ...grpc.CallOption) (*DataFile, error) {
-	out := new(DataFile)
-	err := grpc.Invoke(ctx, "/rannu.Worker/ComputeScores", in, out, c.cc, opts...)
-	if err != nil {
+func _Worker_AssignClusters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Clustering)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(WorkerServer).AssignClusters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/AssignClusters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).AssignClusters(ctx, req.(*Clustering))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// Server API for Worker service
+func _Worker_GetResiduals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinearModel)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).GetResiduals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetResiduals",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetResiduals(ctx, req.(*LinearModel))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-type WorkerServer interface {
-	LoadData(context.Context, *DataFile) (*Size, error)
-	GetSum(context.Context, *Unit) (*Vector, error)
-	GetVariance(context.Context, *Vector) (*Vector, error)
-	GetScatterMatrix(context.Context, *Matrix) (*Matrix, error)
-	ComputeScores(context.Context, *Matrix) (*DataFile, error)
+func _Worker_GetClassMoments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Unit)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).GetClassMoments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetClassMoments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetClassMoments(ctx, req.(*Unit))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterWorkerServer(s *grpc.Server, srv WorkerServer) {
-	s.RegisterService(&_Worker_serviceDesc, srv)
+func _Worker_GetLabeledScores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Matrix)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).GetLabeledScores(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rannu.Worker/GetLabeledScores",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).GetLabeledScores(ctx, req.(*Matrix))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Worker_LoadData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DataFile)
+func _Worker_GetGroupScatter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ColumnGroups)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkerServer).LoadData(ctx, in)
+		return srv.(WorkerServer).GetGroupScatter(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/rannu.Worker/LoadData",
+		FullMethod: "/rannu.Worker/GetGroupScatter",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkerServer).LoadData(ctx, req.(*DataFile))
+		return srv.(WorkerServer).GetGroupScatter(ctx, req.(*ColumnGroups))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Worker_GetSum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Unit)
+func _Worker_GetIndependentStatistics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IndependentModel)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkerServer).GetSum(ctx, in)
+		return srv.(WorkerServer).GetIndependentStatistics(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/rannu.Worker/GetSum",
+		FullMethod: "/rannu.Worker/GetIndependentStatistics",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkerServer).GetSum(ctx, req.(*Unit))
+		return srv.(WorkerServer).GetIndependentStatistics(ctx, req.(*IndependentModel))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Worker_GetVariance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Vector)
+func _Worker_GetColumnStatistics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Unit)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkerServer).GetVariance(ctx, in)
+		return srv.(WorkerServer).GetColumnStatistics(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/rannu.Worker/GetVariance",
+		FullMethod: "/rannu.Worker/GetColumnStatistics",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkerServer).GetVariance(ctx, req.(*Vector))
+		return srv.(WorkerServer).GetColumnStatistics(ctx, req.(*Unit))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Worker_GetScatterMatrix_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Matrix)
+func _Worker_GetColumnSketches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Binning)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkerServer).GetScatterMatrix(ctx, in)
+		return srv.(WorkerServer).GetColumnSketches(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/rannu.Worker/GetScatterMatrix",
+		FullMethod: "/rannu.Worker/GetColumnSketches",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkerServer).GetScatterMatrix(ctx, req.(*Matrix))
+		return srv.(WorkerServer).GetColumnSketches(ctx, req.(*Binning))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Worker_ComputeScores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Matrix)
+func _Worker_GetFourthMoment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Unit)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkerServer).ComputeScores(ctx, in)
+		return srv.(WorkerServer).GetFourthMoment(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/rannu.Worker/ComputeScores",
+		FullMethod: "/rannu.Worker/GetFourthMoment",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkerServer).ComputeScores(ctx, req.(*Matrix))
+		return srv.(WorkerServer).GetFourthMoment(ctx, req.(*Unit))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -296,6 +1468,106 @@ var _Worker_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ComputeScores",
 			Handler:    _Worker_ComputeScores_Handler,
 		},
+		{
+			MethodName: "Standardize",
+			Handler:    _Worker_Standardize_Handler,
+		},
+		{
+			MethodName: "GetRangeSketch",
+			Handler:    _Worker_GetRangeSketch_Handler,
+		},
+		{
+			MethodName: "GetProjectedScatter",
+			Handler:    _Worker_GetProjectedScatter_Handler,
+		},
+		{
+			MethodName: "GetTriangularFactor",
+			Handler:    _Worker_GetTriangularFactor_Handler,
+		},
+		{
+			MethodName: "GetLocalComponents",
+			Handler:    _Worker_GetLocalComponents_Handler,
+		},
+		{
+			MethodName: "GetRowBlock",
+			Handler:    _Worker_GetRowBlock_Handler,
+		},
+		{
+			MethodName: "AppendData",
+			Handler:    _Worker_AppendData_Handler,
+		},
+		{
+			MethodName: "GetRobustMoments",
+			Handler:    _Worker_GetRobustMoments_Handler,
+		},
+		{
+			MethodName: "GetRobustWeights",
+			Handler:    _Worker_GetRobustWeights_Handler,
+		},
+		{
+			MethodName: "GetObservedMoments",
+			Handler:    _Worker_GetObservedMoments_Handler,
+		},
+		{
+			MethodName: "GetLatentStatistics",
+			Handler:    _Worker_GetLatentStatistics_Handler,
+		},
+		{
+			MethodName: "GetPermutedScatter",
+			Handler:    _Worker_GetPermutedScatter_Handler,
+		},
+		{
+			MethodName: "GetBootstrapMoments",
+			Handler:    _Worker_GetBootstrapMoments_Handler,
+		},
+		{
+			MethodName: "GetTrainingMoments",
+			Handler:    _Worker_GetTrainingMoments_Handler,
+		},
+		{
+			MethodName: "GetReconstructionError",
+			Handler:    _Worker_GetReconstructionError_Handler,
+		},
+		{
+			MethodName: "SampleCentroids",
+			Handler:    _Worker_SampleCentroids_Handler,
+		},
+		{
+			MethodName: "AssignClusters",
+			Handler:    _Worker_AssignClusters_Handler,
+		},
+		{
+			MethodName: "GetResiduals",
+			Handler:    _Worker_GetResiduals_Handler,
+		},
+		{
+			MethodName: "GetClassMoments",
+			Handler:    _Worker_GetClassMoments_Handler,
+		},
+		{
+			MethodName: "GetLabeledScores",
+			Handler:    _Worker_GetLabeledScores_Handler,
+		},
+		{
+			MethodName: "GetGroupScatter",
+			Handler:    _Worker_GetGroupScatter_Handler,
+		},
+		{
+			MethodName: "GetIndependentStatistics",
+			Handler:    _Worker_GetIndependentStatistics_Handler,
+		},
+		{
+			MethodName: "GetColumnStatistics",
+			Handler:    _Worker_GetColumnStatistics_Handler,
+		},
+		{
+			MethodName: "GetColumnSketches",
+			Handler:    _Worker_GetColumnSketches_Handler,
+		},
+		{
+			MethodName: "GetFourthMoment",
+			Handler:    _Worker_GetFourthMoment_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: fileDescriptor0,
@@ -304,23 +1576,102 @@ var _Worker_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("rannu.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 275 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x64, 0x51, 0xcd, 0x4a, 0xc4, 0x30,
-	0x10, 0xde, 0xae, 0xbb, 0x61, 0x9d, 0xb2, 0xb8, 0xe4, 0xb4, 0xf4, 0x20, 0x92, 0x83, 0x54, 0xc1,
-	0x82, 0xdd, 0x37, 0x50, 0xd1, 0x8b, 0x5e, 0x5a, 0x5c, 0xcf, 0xb1, 0xce, 0x21, 0xd8, 0x26, 0x4b,
-	0x3a, 0x45, 0xf1, 0xb1, 0x7d, 0x02, 0x9b, 0xb4, 0x55, 0x5a, 0x6f, 0x33, 0xdf, 0xcf, 0xe4, 0xfb,
-	0x08, 0x84, 0x56, 0x6a, 0xdd, 0x24, 0x07, 0x6b, 0xc8, 0xf0, 0xa5, 0x5f, 0x04, 0x83, 0xc5, 0xb3,
-	0x56, 0x24, 0x4e, 0x61, 0x75, 0x27, 0x49, 0xde, 0xab, 0x12, 0x39, 0x87, 0x85, 0x96, 0x15, 0x6e,
-	0x83, 0xb3, 0x20, 0x3e, 0xce, 0xfc, 0x2c, 0x12, 0x58, 0xe4, 0xea, 0xcb, 0x73, 0xd6, 0x7c, 0xd4,
-	0x9e, 0x5b, 0x66, 0x7e, 0x76, 0x58, 0x61, 0xca, 0x7a, 0x3b, 0xef, 0x30, 0x37, 0x8b, 0x18, 0xd8,
-	0x1e, 0x0b, 0x32, 0x96, 0xb7, 0x97, 0xb1, 0xc4, 0x0a, 0x35, 0x39, 0xd7, 0x51, 0x1c, 0xdc, 0xcc,
-	0x37, 0x41, 0xf6, 0x8b, 0x89, 0x1d, 0xb0, 0x27, 0x49, 0x56, 0x7d, 0xf2, 0x8b, 0x89, 0x32, 0x4c,
-	0xd7, 0x49, 0x17, 0xb9, 0x3b, 0xf5, 0x67, 0x4a, 0xbf, 0x03, 0x60, 0x2f, 0xc6, 0xbe, 0xa3, 0xe5,
-	0x97, 0xb0, 0x7a, 0x34, 0xf2, 0xcd, 0xa5, 0xe7, 0x27, 0xbd, 0x7e, 0xa8, 0x12, 0x85, 0x3d, 0xe0,
-	0xb2, 0x8b, 0x19, 0x3f, 0x07, 0xf6, 0x80, 0x94, 0x37, 0x15, 0x1f, 0x08, 0x57, 0x3e, 0x1a, 0x3f,
-	0xd3, 0xea, 0xae, 0x20, 0x6c, 0x75, 0x7b, 0x69, 0x95, 0xd4, 0x05, 0xf2, 0x31, 0xff, 0x5f, 0x9e,
-	0xc2, 0xc6, 0x9d, 0x2d, 0x24, 0x11, 0xda, 0xbe, 0xcc, 0x20, 0xea, 0xd6, 0x68, 0xbc, 0xb6, 0x9e,
-	0x6b, 0x58, 0xdf, 0x9a, 0xea, 0xd0, 0x10, 0xe6, 0x85, 0xb1, 0x58, 0x4f, 0x0d, 0xd3, 0x2a, 0x62,
-	0xf6, 0xca, 0xfc, 0xcf, 0xed, 0x7e, 0x02, 0x00, 0x00, 0xff, 0xff, 0x5d, 0x39, 0x1b, 0x40, 0xc8,
-	0x01, 0x00, 0x00,
+	// 1543 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9d, 0x58, 0x59, 0x6f, 0x1c, 0x45,
+	0x10, 0x66, 0xbc, 0xeb, 0xb5, 0x53, 0x6b, 0x3b, 0xce, 0x84, 0x24, 0xab, 0x55, 0x44, 0xc2, 0x20,
+	0xc0, 0x10, 0xe5, 0xb2, 0x13, 0x41, 0x44, 0x90, 0x88, 0x9d, 0x38, 0x42, 0x72, 0x20, 0x1a, 0x87,
+	0xe4, 0x05, 0x29, 0xb4, 0x67, 0x7a, 0x77, 0x3b, 0x9e, 0x99, 0x5e, 0xe6, 0xb0, 0x0d, 0x42, 0x42,
+	0x79, 0xe2, 0x81, 0x57, 0xf8, 0x17, 0xfc, 0x0b, 0xfe, 0x18, 0x55, 0x7d, 0xcc, 0xb1, 0x3b, 0x76,
+	0x24, 0x5e, 0xec, 0xa9, 0xea, 0xba, 0xba, 0xea, 0xab, 0xea, 0xd2, 0x42, 0x3f, 0x65, 0x49, 0x52,
+	0xdc, 0x9a, 0xa6, 0x32, 0x97, 0xee, 0xa2, 0x22, 0xbc, 0x1e, 0x74, 0x7f, 0x48, 0x44, 0xee, 0xbd,
+	0x86, 0xe5, 0xc7, 0x2c, 0x67, 0xbb, 0x22, 0xe2, 0xae, 0x0b, 0xdd, 0x84, 0xc5, 0x7c, 0xe0, 0x5c,
+	0x77, 0x36, 0xce, 0xf9, 0xea, 0xdb, 0x1d, 0xc2, 0xf2, 0x31, 0x17, 0xe3, 0x49, 0xce, 0xc3, 0xc1,
+	0x02, 0xf2, 0x97, 0xfd, 0x92, 0x76, 0x3f, 0x82, 0x55, 0xfd, 0xfd, 0x3a, 0x90, 0x51, 0x11, 0x27,
+	0x83, 0x0e, 0x0a, 0x2c, 0xfa, 0x2b, 0x9a, 0xb9, 0xa3, 0x78, 0xde, 0x4f, 0xd0, 0xdd, 0x17, 0xbf,
+	0x2a, 0xe3, 0xa9, 0x3c, 0xce, 0x94, 0xf1, 0x45, 0x5f, 0x7d, 0x13, 0x0f, 0x35, 0x33, 0x65, 0x18,
+	0x79, 0xf4, 0xed, 0x5e, 0x86, 0x9e, 0xd6, 0x57, 0xd6, 0x1c, 0xdf, 0x50, 0xee, 0x00, 0x96, 0x62,
+	0x91, 0x65, 0x22, 0x19, 0x0f, 0xba, 0x78, 0xd0, 0xf1, 0x2d, 0xe9, 0x6d, 0x40, 0xef, 0x25, 0x0f,
+	0x72, 0x99, 0xba, 0x1f, 0xc0, 0x32, 0x8f, 0x78, 0xcc, 0x93, 0x9c, 0xfc, 0x74, 0x36, 0x9c, 0xed,
+	0x85, 0x75, 0xc7, 0x2f, 0x79, 0xde, 0x16, 0xf4, 0x9e, 0xb1, 0x3c, 0x15, 0x27, 0xee, 0x67, 0x33,
+	0x92, 0xfd, 0xcd, 0xd5, 0x5b, 0x3a, 0x4b, 0xda, 0x54, 0x4d, 0x69, 0x08, 0xb0, 0x23, 0xe3, 0xa9,
+	0x4c, 0x88, 0x72, 0x57, 0xc0, 0x39, 0x34, 0x77, 0x70, 0x0e, 0xbd, 0xdf, 0x61, 0xe9, 0x99, 0x54,
+	0x62, 0xad, 0xf7, 0xbb, 0x06, 0x9d, 0xac, 0x88, 0xd5, 0xf5, 0xe6, 0x1c, 0xd0, 0x89, 0xfb, 0x29,
+	0x2c, 0x65, 0x01, 0xcb, 0x73, 0x9e, 0xaa, 0xdb, 0x56, 0x42, 0x3a, 0x4c, 0xdf, 0x9e, 0xd6, 0xb2,
+	0xd2, 0xad, 0x67, 0xc5, 0xfb, 0x0d, 0xd6, 0x7c, 0x79, 0x50, 0x64, 0xf9, 0x93, 0x2c, 0x17, 0x31,
+	0xcb, 0xb9, 0xfb, 0x31, 0xf4, 0x02, 0x0c, 0x08, 0x2d, 0x3a, 0x6d, 0x6e, 0xcd, 0xa1, 0x7b, 0x03,
+	0xce, 0x4d, 0x53, 0x1e, 0x88, 0x4c, 0xc8, 0x64, 0x26, 0x40, 0xe3, 0xbb, 0x3a, 0x27, 0xef, 0x41,
+	0x91, 0xcb, 0xd1, 0xc8, 0xd6, 0x44, 0x53, 0xde, 0x77, 0xd0, 0xdf, 0x43, 0x9f, 0x49, 0xfe, 0x4c,
+	0x86, 0x3c, 0xa2, 0xa4, 0x46, 0x92, 0x85, 0x58, 0x93, 0x6c, 0xc6, 0xb9, 0x31, 0x59, 0x1e, 0xbb,
+	0xef, 0xc3, 0x62, 0x22, 0x45, 0xc6, 0x95, 0x6b, 0xc7, 0xd7, 0x84, 0xf7, 0xaf, 0x03, 0xeb, 0xda,
+	0xe0, 0x7e, 0xce, 0x72, 0x81, 0x57, 0x0a, 0x32, 0xb2, 0x8a, 0xc8, 0x0d, 0x8b, 0x20, 0x3f, 0xcd,
+	0xaa, 0x3d, 0xa6, 0x74, 0xc6, 0xba, 0x1c, 0xed, 0x57, 0xb2, 0xa7, 0x04, 0xa6, 0xec, 0xe7, 0x82,
+	0xa5, 0x3c, 0x33, 0x37, 0xb2, 0x24, 0xe1, 0x5d, 0x1e, 0x64, 0x3c, 0x3d, 0x42, 0xbc, 0xeb, 0x54,
+	0x97, 0x34, 0xa6, 0x76, 0x2d, 0x92, 0xe3, 0xd7, 0x91, 0x38, 0xe4, 0x91, 0x98, 0x48, 0x19, 0x0e,
+	0x16, 0x95, 0xc4, 0x2a, 0x72, 0xf7, 0x4a, 0xa6, 0xf7, 0x21, 0xf4, 0x9f, 0xf3, 0x34, 0x2e, 0xe8,
+	0x0a, 0x98, 0x3c, 0x04, 0x46, 0xc6, 0xd1, 0x9a, 0xa3, 0x50, 0xab, 0xbe, 0x3d, 0x04, 0xaa, 0xcf,
+	0x33, 0x16, 0x4f, 0x75, 0xd7, 0xcd, 0x9d, 0x6f, 0x42, 0x77, 0x57, 0x46, 0x21, 0xa5, 0x49, 0x24,
+	0x21, 0x3f, 0x31, 0xa8, 0xd2, 0x04, 0x71, 0x03, 0x59, 0x24, 0xb9, 0xe9, 0x1b, 0x4d, 0x78, 0x7f,
+	0x38, 0x88, 0x05, 0x1e, 0xc8, 0x24, 0xcb, 0x53, 0xcc, 0x06, 0xb9, 0xbe, 0x06, 0xdd, 0x11, 0x9a,
+	0x31, 0x69, 0xeb, 0x9b, 0x64, 0x90, 0x65, 0x5f, 0x1d, 0xd4, 0xc0, 0xb2, 0x70, 0x16, 0x58, 0x6e,
+	0x02, 0x04, 0x65, 0x0b, 0xb4, 0x23, 0xb5, 0x26, 0xe0, 0xfd, 0xe5, 0x60, 0xcb, 0x44, 0x08, 0x4a,
+	0x9e, 0x62, 0xb1, 0x09, 0x6a, 0x64, 0x27, 0x95, 0x22, 0x3c, 0xa5, 0x82, 0xd5, 0x39, 0xb9, 0xc2,
+	0x72, 0xbe, 0xe1, 0xea, 0x02, 0xed, 0x55, 0xac, 0x09, 0x10, 0x32, 0x47, 0x8c, 0x62, 0xb5, 0xc8,
+	0xd4, 0x54, 0x99, 0xd4, 0x6e, 0x2d, 0xa9, 0x31, 0xf4, 0x4d, 0x54, 0xfb, 0x45, 0x9c, 0xb9, 0x1f,
+	0xa2, 0x08, 0xfe, 0x6f, 0x8f, 0x48, 0x1d, 0x11, 0x9e, 0x74, 0x9f, 0x65, 0xed, 0xf9, 0xb1, 0xa7,
+	0x7a, 0x90, 0x65, 0x76, 0x64, 0xa9, 0x6f, 0xef, 0x08, 0x9b, 0x43, 0x24, 0x9c, 0xa5, 0xba, 0x39,
+	0x30, 0xd2, 0x9c, 0xa5, 0x63, 0x9e, 0x9b, 0x5a, 0x1a, 0xca, 0xbd, 0x0b, 0x2b, 0x81, 0xe4, 0xa3,
+	0x91, 0x08, 0x44, 0x0b, 0x70, 0x8d, 0xa3, 0x86, 0x88, 0x7b, 0x15, 0xce, 0x09, 0xaa, 0x4b, 0xc0,
+	0xa7, 0xd6, 0x65, 0xc5, 0xf0, 0xfe, 0x76, 0xe0, 0x3c, 0x82, 0x4b, 0x84, 0x05, 0x8b, 0xf0, 0xa2,
+	0x31, 0x4b, 0x7f, 0xa9, 0x8d, 0x0f, 0xa7, 0x31, 0x54, 0xd7, 0xab, 0x01, 0xe5, 0xe8, 0x89, 0x74,
+	0x66, 0x67, 0xb0, 0x83, 0x0c, 0x87, 0x7a, 0xce, 0x6d, 0x67, 0x58, 0x9a, 0xec, 0xc4, 0x22, 0x31,
+	0xed, 0x40, 0x9f, 0x8a, 0xc3, 0x4e, 0x06, 0x3d, 0xc3, 0x61, 0x27, 0xde, 0x73, 0x58, 0xd9, 0x89,
+	0x58, 0x96, 0xd9, 0x81, 0x89, 0x31, 0x45, 0xec, 0x80, 0x47, 0x66, 0x54, 0xfb, 0x86, 0x72, 0x37,
+	0xea, 0x4d, 0x4c, 0x93, 0x79, 0xcd, 0x96, 0x46, 0x73, 0xcb, 0x2e, 0xf6, 0x1e, 0xa2, 0x45, 0xf5,
+	0xc8, 0x3c, 0x4d, 0x65, 0x31, 0x55, 0x43, 0x65, 0x24, 0xd2, 0x2c, 0x57, 0x06, 0xb1, 0x2f, 0x14,
+	0x41, 0x7e, 0x32, 0x6a, 0x8b, 0x50, 0x99, 0xc3, 0xc4, 0x6b, 0xca, 0x7b, 0x03, 0xeb, 0xdf, 0x62,
+	0x3b, 0x4d, 0x39, 0xfe, 0xb1, 0x13, 0x0c, 0xa1, 0x7a, 0x3c, 0x11, 0x38, 0x80, 0xe8, 0x99, 0x69,
+	0x87, 0x6a, 0x79, 0x4e, 0x83, 0xa9, 0x48, 0x62, 0x71, 0x42, 0xb2, 0xad, 0x40, 0x2d, 0x8f, 0xbd,
+	0x3f, 0x1d, 0xb8, 0x54, 0x73, 0xf6, 0xff, 0xa6, 0xdb, 0x6d, 0xe8, 0x87, 0xd8, 0x51, 0x47, 0xa8,
+	0x7b, 0xc4, 0x4f, 0x01, 0x4a, 0x5d, 0xe2, 0xb4, 0xa7, 0xd4, 0xfb, 0x07, 0xc7, 0xac, 0x4e, 0x5c,
+	0x2d, 0x90, 0x3a, 0x44, 0x3a, 0xed, 0xef, 0x2e, 0xe5, 0xaf, 0x7a, 0x77, 0x6d, 0xd1, 0x3b, 0x4a,
+	0xbc, 0x5e, 0xf4, 0xae, 0xe1, 0xb0, 0x13, 0x6a, 0x8c, 0x98, 0x33, 0x42, 0x06, 0xb1, 0xd4, 0xb7,
+	0xbb, 0x06, 0x0b, 0xf1, 0x26, 0x22, 0x83, 0x38, 0xf8, 0xa5, 0xe8, 0xad, 0xc1, 0x92, 0xa1, 0xb7,
+	0x14, 0x7d, 0x6f, 0xb0, 0x6c, 0xe8, 0x7b, 0xde, 0x5b, 0x07, 0x96, 0xb6, 0x45, 0xa2, 0x72, 0x8e,
+	0xf6, 0x0e, 0x44, 0x52, 0xbe, 0xb2, 0xf4, 0x4d, 0xaf, 0x2c, 0xc5, 0xd1, 0xfe, 0xca, 0x52, 0x58,
+	0xd7, 0x74, 0x58, 0x9d, 0x76, 0x01, 0x8c, 0xf2, 0x3a, 0xf4, 0x69, 0x7c, 0x21, 0xca, 0xd5, 0x73,
+	0xa8, 0xd1, 0x5d, 0x67, 0x79, 0x3f, 0x42, 0xef, 0xb1, 0x18, 0x73, 0x84, 0x13, 0x82, 0x8c, 0x6e,
+	0x61, 0x51, 0xab, 0x09, 0xca, 0x52, 0x35, 0x29, 0x88, 0x5f, 0x8e, 0x86, 0x32, 0x4b, 0xce, 0x5c,
+	0x96, 0xca, 0xd6, 0x98, 0xc0, 0x9a, 0xa9, 0xc7, 0x21, 0xcf, 0x83, 0x09, 0x57, 0x93, 0x27, 0x54,
+	0xfe, 0x66, 0xd7, 0x13, 0x1d, 0x85, 0x6f, 0x4f, 0x69, 0x5e, 0x4e, 0xb0, 0x82, 0x72, 0x9c, 0xb2,
+	0xf8, 0x94, 0x57, 0xaf, 0x26, 0xb0, 0xf9, 0x76, 0x15, 0x7a, 0xaf, 0x64, 0x7a, 0x88, 0x43, 0xfd,
+	0x73, 0x58, 0xde, 0xc3, 0xe7, 0x98, 0xb6, 0x3f, 0xf7, 0xbc, 0xb5, 0x6e, 0x56, 0xc1, 0xa1, 0x7d,
+	0x2b, 0x68, 0x75, 0xf3, 0xde, 0x73, 0x3f, 0x81, 0xde, 0x53, 0x9e, 0xe3, 0x34, 0x71, 0xed, 0x01,
+	0x2d, 0x8f, 0xc3, 0x66, 0x2e, 0x51, 0xee, 0x26, 0xf4, 0x51, 0xee, 0x25, 0x4b, 0x05, 0x4b, 0x02,
+	0xee, 0x36, 0xcf, 0xe7, 0xc5, 0x37, 0x61, 0x9d, 0xcc, 0xea, 0x1d, 0xc7, 0x6c, 0x66, 0xcd, 0xe0,
+	0x87, 0x4d, 0x12, 0x75, 0xee, 0xc2, 0x2a, 0xad, 0x63, 0x38, 0x75, 0xf6, 0x03, 0x49, 0x73, 0x69,
+	0x46, 0x61, 0xf6, 0x2a, 0xa8, 0x72, 0x03, 0xfa, 0x08, 0xf4, 0x24, 0x64, 0x69, 0x48, 0x9b, 0xe8,
+	0x8c, 0x42, 0xfd, 0x46, 0x28, 0x7c, 0x07, 0xd6, 0x30, 0x26, 0x9f, 0x25, 0x63, 0xae, 0xab, 0xf1,
+	0xce, 0x88, 0xee, 0xc3, 0x45, 0xd4, 0x78, 0xae, 0x1f, 0x25, 0x1e, 0x9a, 0xeb, 0xbc, 0x53, 0x6d,
+	0x4b, 0xa9, 0xbd, 0xa0, 0x54, 0x8d, 0x8b, 0x88, 0xa5, 0xbb, 0xfa, 0xe5, 0x6a, 0x4d, 0x70, 0xa9,
+	0xf4, 0x25, 0xb8, 0xa8, 0xb4, 0x27, 0x03, 0x16, 0xd5, 0x96, 0xd2, 0x0b, 0x46, 0xac, 0x62, 0xcd,
+	0x6b, 0xde, 0x50, 0xa5, 0xf1, 0xe5, 0xf1, 0x76, 0x24, 0x83, 0xc3, 0x77, 0xb8, 0xb9, 0x0d, 0xf0,
+	0x68, 0x4a, 0xc3, 0xaa, 0x1d, 0x1d, 0x33, 0x13, 0x19, 0x15, 0xbe, 0x52, 0x95, 0xd4, 0xab, 0xa8,
+	0x1d, 0xf0, 0x97, 0x8c, 0x54, 0x73, 0x41, 0x6d, 0x51, 0xbe, 0x53, 0x53, 0x7e, 0x65, 0xda, 0xe6,
+	0x6c, 0x9c, 0x6d, 0xaa, 0x34, 0x7c, 0x6f, 0x16, 0x33, 0xeb, 0xf0, 0xec, 0x3b, 0x3d, 0x56, 0xf9,
+	0x9e, 0x5b, 0x2f, 0x5d, 0x23, 0x57, 0x5b, 0x64, 0x87, 0x57, 0x1a, 0xbc, 0x4a, 0x18, 0xad, 0x3c,
+	0x50, 0x9e, 0xf5, 0x7e, 0x57, 0xd5, 0xda, 0x1a, 0xa9, 0xed, 0x7d, 0x6d, 0xb5, 0xa3, 0x00, 0xb6,
+	0xa5, 0xcc, 0x71, 0x43, 0x63, 0x53, 0x1b, 0xb5, 0xcd, 0xae, 0x5d, 0x08, 0x5b, 0x12, 0xb4, 0xa5,
+	0x9c, 0xbe, 0x48, 0x99, 0xa0, 0x21, 0x38, 0x7b, 0x5d, 0xda, 0xe7, 0x5a, 0x94, 0xbe, 0x81, 0xcb,
+	0x94, 0xd5, 0xc6, 0x46, 0xf8, 0x24, 0x4d, 0x11, 0x62, 0x65, 0x61, 0x1a, 0x67, 0xf3, 0x59, 0xbe,
+	0x0f, 0xe7, 0xf7, 0x55, 0x48, 0x3b, 0xe5, 0x7a, 0x56, 0x22, 0xad, 0x5c, 0xef, 0xe6, 0xef, 0xf9,
+	0x00, 0xd6, 0x1e, 0xe1, 0xd4, 0x1c, 0x27, 0x46, 0xa8, 0x55, 0xcb, 0x6d, 0xb2, 0x68, 0x23, 0x43,
+	0x55, 0x7c, 0xd1, 0x55, 0xcc, 0x7a, 0x7b, 0xa9, 0x15, 0xa7, 0x5a, 0xa4, 0x86, 0x97, 0xab, 0x7c,
+	0xd5, 0x77, 0x1c, 0x1d, 0x2f, 0x6a, 0x37, 0x96, 0x8c, 0x06, 0x24, 0x2e, 0x96, 0x3e, 0x2b, 0x89,
+	0x72, 0x0a, 0xed, 0xd1, 0xf6, 0x41, 0x15, 0x6d, 0x1b, 0x2a, 0x73, 0x77, 0xfc, 0x42, 0xb9, 0x52,
+	0x7b, 0x87, 0xc5, 0x40, 0x69, 0xbd, 0xb6, 0x92, 0xcc, 0x2b, 0xee, 0xc3, 0x00, 0x15, 0xdb, 0x77,
+	0x01, 0x0b, 0xbb, 0xd9, 0xb5, 0x64, 0x78, 0x75, 0xfe, 0xa0, 0x01, 0xca, 0xaf, 0x15, 0xb2, 0xe6,
+	0x9e, 0xf4, 0xc6, 0xe5, 0xaf, 0x34, 0xc2, 0x6b, 0xa8, 0x3f, 0x84, 0x0b, 0x95, 0xba, 0x7d, 0x81,
+	0x2c, 0xa0, 0xcc, 0xcb, 0x3b, 0xbc, 0xd4, 0xd4, 0x37, 0x62, 0x6a, 0x56, 0x50, 0x2a, 0x76, 0x65,
+	0x91, 0xe6, 0x13, 0x9d, 0xd4, 0xb3, 0x9b, 0xf7, 0xa0, 0xa7, 0x7e, 0x88, 0xd8, 0xfa, 0x0f, 0x14,
+	0x39, 0xee, 0xa0, 0x97, 0x10, 0x00, 0x00,
 }
@@ -0,0 +1,193 @@
+// Package sketch provides the mergeable summaries of column distributions
+// that the workers build over their partitions and the coordinator merges
+package sketch
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultCompression bounds the number of centroids a digest keeps, which is
+// about twice the compression, and so trades size for accuracy
+const DefaultCompression = 100
+
+// Digest is a merging t-digest of weighted values. Values are summarized by
+// centroids whose weight is limited by the arcsine scale function, so the
+// centroids are small near the extremes and quantiles in the tails stay
+// accurate. Digests of different partitions merge into a digest of their
+// union with the same accuracy guarantees
+type Digest struct {
+	compression float64
+	centroids   []centroid // sorted by mean once compressed
+	pending     []centroid
+	total       float64
+	min         float64
+	max         float64
+}
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// New returns an empty digest with the given compression
+func New(compression float64) *Digest {
+	return &Digest{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// FromCentroids rebuilds a digest from the centroids, smallest and largest
+// values returned by Centroids, Min and Max
+func FromCentroids(compression float64, means []float64, weights []float64, min float64, max float64) *Digest {
+	d := New(compression)
+	for i := range means {
+		d.pending = append(d.pending, centroid{means[i], weights[i]})
+		d.total += weights[i]
+	}
+	if len(means) > 0 {
+		d.min = min
+		d.max = max
+	}
+	d.compress()
+	return d
+}
+
+// Add adds a value with the given weight. Values without weight are ignored
+func (d *Digest) Add(x float64, weight float64) {
+	if weight <= 0 {
+		return
+	}
+	d.pending = append(d.pending, centroid{x, weight})
+	d.total += weight
+	d.min = math.Min(d.min, x)
+	d.max = math.Max(d.max, x)
+	if len(d.pending) > int(10*d.compression) {
+		d.compress()
+	}
+}
+
+// Merge adds the values summarized by another digest
+func (d *Digest) Merge(other *Digest) {
+	other.compress()
+	d.pending = append(d.pending, other.centroids...)
+	d.total += other.total
+	d.min = math.Min(d.min, other.min)
+	d.max = math.Max(d.max, other.max)
+	d.compress()
+}
+
+// Centroids returns the means and weights of the centroids in ascending order
+// of mean
+func (d *Digest) Centroids() ([]float64, []float64) {
+	d.compress()
+	means := make([]float64, len(d.centroids))
+	weights := make([]float64, len(d.centroids))
+	for i, c := range d.centroids {
+		means[i] = c.mean
+		weights[i] = c.weight
+	}
+	return means, weights
+}
+
+// Min returns the smallest value added, or +Inf for an empty digest
+func (d *Digest) Min() float64 {
+	return d.min
+}
+
+// Max returns the largest value added, or -Inf for an empty digest
+func (d *Digest) Max() float64 {
+	return d.max
+}
+
+// Weight returns the total weight of the values added
+func (d *Digest) Weight() float64 {
+	return d.total
+}
+
+// Quantile returns an estimate of the value below which the fraction q of the
+// total weight lies, interpolating linearly between the centroid means and
+// the extreme values. It returns NaN for an empty digest
+func (d *Digest) Quantile(q float64) float64 {
+	d.compress()
+	n := len(d.centroids)
+	if n == 0 {
+		return math.NaN()
+	}
+	if n == 1 {
+		return d.centroids[0].mean
+	}
+
+	target := math.Max(0, math.Min(1, q)) * d.total
+	first := d.centroids[0]
+	if target < first.weight/2 {
+		return d.min + (first.mean-d.min)*target/(first.weight/2)
+	}
+	last := d.centroids[n-1]
+	if target > d.total-last.weight/2 {
+		return last.mean + (d.max-last.mean)*(target-d.total+last.weight/2)/(last.weight/2)
+	}
+
+	// The weight of each centroid is taken to be spread evenly around its
+	// mean, so centroid i sits at cumulative weight before + weight/2
+	before := 0.0
+	for i := 0; i < n-1; i++ {
+		left := before + d.centroids[i].weight/2
+		right := left + (d.centroids[i].weight+d.centroids[i+1].weight)/2
+		if target <= right {
+			fraction := (target - left) / (right - left)
+			return d.centroids[i].mean + fraction*(d.centroids[i+1].mean-d.centroids[i].mean)
+		}
+		before += d.centroids[i].weight
+	}
+	return last.mean
+}
+
+// compress merges the pending values into the centroids. Adjacent centroids
+// are combined while the span of their combined weight under the scale
+// function stays within one
+func (d *Digest) compress() {
+	if len(d.pending) == 0 {
+		return
+	}
+	all := append(d.centroids, d.pending...)
+	sort.Sort(byMean(all))
+	d.pending = nil
+
+	merged := []centroid{all[0]}
+	var before float64
+	for _, c := range all[1:] {
+		current := &merged[len(merged)-1]
+		limit := d.scale((before+current.weight+c.weight)/d.total) - d.scale(before/d.total)
+		if limit <= 1 {
+			current.mean += (c.mean - current.mean) * c.weight / (current.weight + c.weight)
+			current.weight += c.weight
+			continue
+		}
+		before += current.weight
+		merged = append(merged, c)
+	}
+	d.centroids = merged
+}
+
+// scale is the arcsine scale function k(q) = δ/2π asin(2q - 1)
+func (d *Digest) scale(q float64) float64 {
+	return d.compression / (2 * math.Pi) * math.Asin(math.Max(-1, math.Min(1, 2*q-1)))
+}
+
+// byMean sorts centroids in ascending order of mean
+type byMean []centroid
+
+func (c byMean) Len() int {
+	return len(c)
+}
+
+func (c byMean) Less(i, j int) bool {
+	return c[i].mean < c[j].mean
+}
+
+func (c byMean) Swap(i, j int) {
+	c[i], c[j] = c[j], c[i]
+}
@@ -0,0 +1,141 @@
+package sketch
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// uniformValues returns the integers 0 to n-1 in a shuffled order
+func uniformValues(n int) []float64 {
+	values := make([]float64, n)
+	for i, j := range rand.New(rand.NewSource(1)).Perm(n) {
+		values[i] = float64(j)
+	}
+	return values
+}
+
+func TestQuantileUniform(t *testing.T) {
+	n := 10000
+	d := New(DefaultCompression)
+	for _, x := range uniformValues(n) {
+		d.Add(x, 1)
+	}
+
+	if d.Weight() != float64(n) {
+		t.Errorf("Weight() = %v, want %v", d.Weight(), n)
+	}
+	if d.Min() != 0 || d.Max() != float64(n-1) {
+		t.Errorf("Min(), Max() = %v, %v, want 0, %v", d.Min(), d.Max(), n-1)
+	}
+
+	tests := []struct {
+		q         float64
+		want      float64
+		tolerance float64
+	}{
+		{0, 0, 0},
+		{0.001, 10, 5},
+		{0.01, 100, 5},
+		{0.25, 2500, 50},
+		{0.5, 5000, 50},
+		{0.75, 7500, 50},
+		{0.99, 9900, 5},
+		{0.999, 9990, 5},
+		{1, float64(n - 1), 0},
+	}
+	for _, test := range tests {
+		got := d.Quantile(test.q)
+		if math.Abs(got-test.want) > test.tolerance {
+			t.Errorf("Quantile(%v) = %v, want %v ± %v", test.q, got, test.want, test.tolerance)
+		}
+	}
+}
+
+func TestQuantileWeighted(t *testing.T) {
+	// a value of weight three counts as three values of weight one
+	d := New(DefaultCompression)
+	for i := 0; i < 1000; i++ {
+		d.Add(float64(i), 1)
+		if i < 500 {
+			d.Add(float64(i), 2)
+		}
+	}
+
+	if got, want := d.Quantile(0.5), 333.0; math.Abs(got-want) > 10 {
+		t.Errorf("Quantile(0.5) = %v, want %v ± 10", got, want)
+	}
+}
+
+func TestMergeMatchesSingleDigest(t *testing.T) {
+	values := uniformValues(10000)
+	whole := New(DefaultCompression)
+	for _, x := range values {
+		whole.Add(x, 1)
+	}
+
+	merged := New(DefaultCompression)
+	parts := 4
+	for p := 0; p < parts; p++ {
+		part := New(DefaultCompression)
+		for _, x := range values[p*len(values)/parts : (p+1)*len(values)/parts] {
+			part.Add(x, 1)
+		}
+		merged.Merge(part)
+	}
+
+	if merged.Weight() != whole.Weight() {
+		t.Errorf("merged Weight() = %v, want %v", merged.Weight(), whole.Weight())
+	}
+	if merged.Min() != whole.Min() || merged.Max() != whole.Max() {
+		t.Errorf("merged Min(), Max() = %v, %v, want %v, %v", merged.Min(), merged.Max(), whole.Min(), whole.Max())
+	}
+	for _, q := range []float64{0, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 1} {
+		if got, want := merged.Quantile(q), whole.Quantile(q); math.Abs(got-want) > 50 {
+			t.Errorf("merged Quantile(%v) = %v, want %v ± 50", q, got, want)
+		}
+	}
+}
+
+func TestFromCentroids(t *testing.T) {
+	d := New(DefaultCompression)
+	for _, x := range uniformValues(1000) {
+		d.Add(x, 1)
+	}
+	means, weights := d.Centroids()
+	rebuilt := FromCentroids(DefaultCompression, means, weights, d.Min(), d.Max())
+
+	if rebuilt.Weight() != d.Weight() || rebuilt.Min() != d.Min() || rebuilt.Max() != d.Max() {
+		t.Errorf("rebuilt Weight(), Min(), Max() = %v, %v, %v, want %v, %v, %v",
+			rebuilt.Weight(), rebuilt.Min(), rebuilt.Max(), d.Weight(), d.Min(), d.Max())
+	}
+	for _, q := range []float64{0, 0.1, 0.5, 0.9, 1} {
+		if got, want := rebuilt.Quantile(q), d.Quantile(q); got != want {
+			t.Errorf("rebuilt Quantile(%v) = %v, want %v", q, got, want)
+		}
+	}
+}
+
+func TestEmptyDigest(t *testing.T) {
+	d := New(DefaultCompression)
+	d.Add(1, 0)
+	d.Merge(New(DefaultCompression))
+
+	if d.Weight() != 0 {
+		t.Errorf("Weight() = %v, want 0", d.Weight())
+	}
+	if !math.IsInf(d.Min(), 1) || !math.IsInf(d.Max(), -1) {
+		t.Errorf("Min(), Max() = %v, %v, want +Inf, -Inf", d.Min(), d.Max())
+	}
+	if q := d.Quantile(0.5); !math.IsNaN(q) {
+		t.Errorf("Quantile(0.5) = %v, want NaN", q)
+	}
+	if means, weights := d.Centroids(); len(means) != 0 || len(weights) != 0 {
+		t.Errorf("Centroids() = %v, %v, want none", means, weights)
+	}
+
+	empty := FromCentroids(DefaultCompression, nil, nil, 0, 0)
+	if !math.IsInf(empty.Min(), 1) || !math.IsInf(empty.Max(), -1) || !math.IsNaN(empty.Quantile(0.5)) {
+		t.Errorf("FromCentroids of no centroids: Min() = %v, Max() = %v, Quantile(0.5) = %v", empty.Min(), empty.Max(), empty.Quantile(0.5))
+	}
+}
@@ -8,8 +8,10 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"math/rand"
 	"net"
 	"os"
+	"sort"
 	"strconv"
 
 	"google.golang.org/grpc"
@@ -19,7 +21,9 @@ import (
 
 	"github.com/montanaflynn/stats"
 	matrix "github.com/skelterjohn/go.matrix"
+	"github.com/unchartedsoftware/rannu/cluster/linalg"
 	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+	"github.com/unchartedsoftware/rannu/cluster/sketch"
 )
 
 var (
@@ -28,7 +32,14 @@ var (
 
 type workerServer struct {
 	filename string
+	appended []string
+	raw      *matrix.DenseMatrix
+	weights  []float64
 	matrix   *matrix.DenseMatrix
+	mean     []float64
+	sd       []float64
+	robust   []float64
+	clusters []int
 }
 
 // Load Data loads a CSV file into a matrix and returns the size
@@ -36,52 +47,122 @@ type workerServer struct {
 func (w *workerServer) LoadData(ctx context.Context, file *pb.DataFile) (*pb.Size, error) {
 	grpclog.Printf("Processing %s...", file.Name)
 	w.filename = file.Name
+	w.appended = nil
+	w.mean = nil
+	w.sd = nil
+	w.robust = nil
+	w.clusters = nil
 
-	cols := 0
-	vectors := [][]float64{}
+	vectors, cols, err := readMatrix(file.Name)
+	if err != nil {
+		return nil, err
+	}
 
-	f, err := os.Open(fmt.Sprintf("data/%s", file.Name))
+	vectors, weights, err := splitWeights(vectors, file)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
+	if weights != nil && cols > 0 {
+		cols--
+	}
 
-	r := csv.NewReader(bufio.NewReader(f))
-	for {
-		row, err := r.Read()
-		if err == io.EOF {
-			break
-		}
+	w.raw = matrix.MakeDenseMatrixStacked(vectors)
+	w.weights = weights
+	w.matrix = w.raw.Copy()
+	grpclog.Printf("Processed %d x %d matrix", len(vectors), cols)
 
-		num := len(row)
-		if cols == 0 {
-			cols = num
-		} else if num != cols {
-			return nil, errors.New("Inconsistent vector sizes")
-		}
+	size := &pb.Size{
+		Rows:    int32(len(vectors)),
+		Cols:    int32(cols),
+		Weight:  totalWeight(weights, len(vectors)),
+		Missing: countMissing(vectors),
+	}
+	return size, nil
+}
 
-		vector := make([]float64, num)
-		for i := range vector {
-			vector[i], err = strconv.ParseFloat(row[i], 64)
-			if err != nil {
-				return nil, err
-			}
-		}
+// AppendData loads a CSV file of new rows, appends them to the matrix and
+// returns the number and total weight of the new rows along with their
+// weighted sum and their weighted scatter matrix about their own mean so that
+// the coordinator can merge them into the statistics it already holds
+func (w *workerServer) AppendData(ctx context.Context, file *pb.DataFile) (*pb.Moments, error) {
+	if w.raw == nil {
+		return nil, errors.New("No matrix available")
+	}
+	grpclog.Printf("Appending %s...", file.Name)
 
-		vectors = append(vectors, vector)
+	vectors, cols, err := readMatrix(file.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) == 0 {
+		return nil, errors.New("No rows to append")
+	}
+	vectors, weights, err := splitWeights(vectors, file)
+	if err != nil {
+		return nil, err
+	}
+	if countMissing(vectors) > 0 {
+		return nil, errors.New("Cannot append rows with missing values")
+	}
+	if weights != nil && cols > 0 {
+		cols--
+	}
+	if cols != w.raw.Cols() || (weights == nil) != (w.weights == nil) {
+		return nil, errors.New("Inconsistent vector sizes")
 	}
 
-	w.matrix = matrix.MakeDenseMatrixStacked(vectors)
-	grpclog.Printf("Processed %d x %d matrix", len(vectors), cols)
+	update := matrix.MakeDenseMatrixStacked(vectors)
+	raw, err := w.raw.Stack(update)
+	if err != nil {
+		return nil, err
+	}
+	w.raw = raw
+	if weights != nil {
+		w.weights = append(w.weights, weights...)
+	}
+	w.matrix = w.raw.Copy()
+	w.mean = nil
+	w.sd = nil
+	w.robust = nil
+	w.clusters = nil
+	w.appended = append(w.appended, file.Name)
+	grpclog.Printf("Appended %d rows for %d x %d matrix", len(vectors), raw.Rows(), cols)
 
-	size := &pb.Size{
-		Rows: int32(len(vectors)),
-		Cols: int32(cols),
+	weight := totalWeight(weights, len(vectors))
+	if weight <= 0 {
+		return nil, errors.New("Appended rows have no weight")
 	}
-	return size, nil
+
+	sum := make([]float64, cols)
+	for i, vector := range vectors {
+		for j, x := range vector {
+			sum[j] += rowWeight(weights, i) * x
+		}
+	}
+
+	centered := update.Copy()
+	for i := 0; i < centered.Rows(); i++ {
+		scale := math.Sqrt(rowWeight(weights, i))
+		for j := 0; j < cols; j++ {
+			centered.Set(i, j, scale*(centered.Get(i, j)-sum[j]/weight))
+		}
+	}
+	scatter, err := centered.Transpose().TimesDense(centered)
+	if err != nil {
+		return nil, err
+	}
+
+	moments := &pb.Moments{
+		Rows:    int32(len(vectors)),
+		Sum:     &pb.Vector{Elements: sum},
+		Scatter: toProto(scatter),
+		Weight:  weight,
+	}
+	return moments, nil
 }
 
-// GetSum returns a vector with the sum of each column as an element
+// GetSum returns a vector with the sum of each column as an element. When the
+// rows are weighted each element is multiplied by the weight of its row
 func (w *workerServer) GetSum(ctx context.Context, unit *pb.Unit) (*pb.Vector, error) {
 	if w.matrix == nil {
 		return nil, errors.New("No matrix available")
@@ -90,6 +171,15 @@ func (w *workerServer) GetSum(ctx context.Context, unit *pb.Unit) (*pb.Vector, e
 		Elements: make([]float64, w.matrix.Cols()),
 	}
 
+	if w.weights != nil {
+		for i := 0; i < w.raw.Rows(); i++ {
+			for j := range sum.Elements {
+				sum.Elements[j] += w.weights[i] * w.raw.Get(i, j)
+			}
+		}
+		return sum, nil
+	}
+
 	var err error
 	for i := range sum.Elements {
 		col := w.matrix.GetColVector(i).Transpose()
@@ -103,7 +193,8 @@ func (w *workerServer) GetSum(ctx context.Context, unit *pb.Unit) (*pb.Vector, e
 }
 
 // GetVariance receives a mean vector and returns a vector, each element of
-// which consits of the sum of the squares of each column element minus the mean
+// which consits of the sum of the squares of each column element minus the mean.
+// When the rows are weighted each square is multiplied by the weight of its row
 func (w *workerServer) GetVariance(ctx context.Context, mean *pb.Vector) (*pb.Vector, error) {
 	if w.matrix == nil {
 		return nil, errors.New("No matrix available")
@@ -114,8 +205,8 @@ func (w *workerServer) GetVariance(ctx context.Context, mean *pb.Vector) (*pb.Ve
 
 	for i := range variance.Elements {
 		col := w.matrix.GetColVector(i).Transpose().Array()
-		for _, x := range col {
-			variance.Elements[i] += math.Pow(x-mean.Elements[i], 2)
+		for r, x := range col {
+			variance.Elements[i] += rowWeight(w.weights, r) * math.Pow(x-mean.Elements[i], 2)
 		}
 	}
 
@@ -124,35 +215,14 @@ func (w *workerServer) GetVariance(ctx context.Context, mean *pb.Vector) (*pb.Ve
 
 // GetScatterMatrix receives mean and standard deviation vectors as a matrix
 // and uses those to standardize the elements of the matrix before returning
-// the sum of the outer product of the rows
+// the sum of the outer product of the rows, weighted by the row weights
 func (w *workerServer) GetScatterMatrix(ctx context.Context, meanAndSD *pb.Matrix) (*pb.Matrix, error) {
-	if len(meanAndSD.Elements) != 2 {
-		return nil, errors.New("Invalid matrix. Need mean and standard deviation rows.")
-	}
-
-	mean := meanAndSD.Elements[0]
-	sd := meanAndSD.Elements[1]
-
-	numRows, numCols := w.matrix.GetSize()
-
-	rows := make([][]float64, numRows)
-	for i := range rows {
-		rows[i] = mean.Elements
-	}
-
-	meanMatrix := matrix.MakeDenseMatrixStacked(rows)
-
-	err := w.matrix.SubtractDense(meanMatrix)
+	err := w.standardize(meanAndSD)
 	if err != nil {
 		return nil, err
 	}
 
-	for i := 0; i < numRows; i++ {
-		for j := 0; j < numCols; j++ {
-			val := w.matrix.Get(i, j)
-			w.matrix.Set(i, j, val/sd.Elements[j])
-		}
-	}
+	numRows, numCols := w.matrix.GetSize()
 
 	scatter := matrix.Zeros(numCols, numCols)
 
@@ -183,82 +253,1271 @@ func (w *workerServer) GetScatterMatrix(ctx context.Context, meanAndSD *pb.Matri
 	return mat, nil
 }
 
-// ComputeScores receives a matrix of top principal component vectors and
-// projects its rows onto that subspace before returning the projection along
-// with the classifiation of each row
-func (w *workerServer) ComputeScores(ctx context.Context, top *pb.Matrix) (*pb.DataFile, error) {
-	k := len(top.Elements)
-	topVectors := make([][]float64, k)
-	for i := range topVectors {
-		topVectors[i] = top.Elements[i].Elements
+// GetFourthMoment returns a vector whose single element is the sum of the
+// fourth powers of the norms of the standardized rows, weighted by the row
+// weights. The Ledoit-Wolf shrinkage needs it to estimate how much the outer
+// products of the rows vary around the scatter matrix
+func (w *workerServer) GetFourthMoment(ctx context.Context, unit *pb.Unit) (*pb.Vector, error) {
+	if w.matrix == nil {
+		return nil, errors.New("No matrix available")
 	}
-	p := matrix.MakeDenseMatrixStacked(topVectors)
 
-	vectors := make([][]float64, w.matrix.Rows())
-	for i := range vectors {
-		vector, err := p.TimesDense(w.matrix.GetRowVector(i).Transpose())
-		if err != nil {
-			return nil, err
+	var sum float64
+	for i := 0; i < w.matrix.Rows(); i++ {
+		weight := rowWeight(w.weights, i)
+		if weight <= 0 {
+			continue
 		}
-		vectors[i] = vector.Transpose().Array()
+		var norm float64
+		for j := 0; j < w.matrix.Cols(); j++ {
+			norm += w.matrix.Get(i, j) * w.matrix.Get(i, j)
+		}
+		// The standardized rows are scaled by the square root of their weight
+		sum += norm * norm / weight
 	}
 
-	in, err := os.Open(fmt.Sprintf("data/answers-%s", w.filename))
+	return &pb.Vector{Elements: []float64{sum}}, nil
+}
+
+// Standardize receives mean and standard deviation vectors as a matrix and
+// uses those to standardize the elements of the matrix in place so that
+// subsequent requests operate on the centered and scaled data
+func (w *workerServer) Standardize(ctx context.Context, meanAndSD *pb.Matrix) (*pb.Unit, error) {
+	err := w.standardize(meanAndSD)
 	if err != nil {
 		return nil, err
 	}
-	defer in.Close()
 
-	answers := []float64{}
-	r := csv.NewReader(bufio.NewReader(in))
-	for {
-		row, err := r.Read()
-		if err == io.EOF {
-			break
-		}
+	return &pb.Unit{}, nil
+}
 
-		if len(row) != 1 {
-			return nil, errors.New("Inconsistent answer vector size")
+// GetRangeSketch receives a cols x l matrix whose columns are a batch of
+// vectors and returns the cols x l product of the transposed data matrix, the
+// data matrix and the batch. Summed across workers this is the scatter matrix
+// applied to each vector without ever forming the scatter matrix, which serves
+// both randomized sketching and power iteration
+func (w *workerServer) GetRangeSketch(ctx context.Context, test *pb.Matrix) (*pb.Matrix, error) {
+	if w.matrix == nil {
+		return nil, errors.New("No matrix available")
+	}
+
+	omega := toDense(test)
+	if omega.Rows() != w.matrix.Cols() {
+		return nil, errors.New("Inconsistent test matrix size")
+	}
+
+	y, err := w.matrix.TimesDense(omega)
+	if err != nil {
+		return nil, err
+	}
+
+	sketch, err := w.matrix.Transpose().TimesDense(y)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProto(sketch), nil
+}
+
+// GetProjectedScatter receives a cols x l matrix with orthonormal columns,
+// projects the rows of the data matrix onto those columns and returns the
+// l x l scatter matrix of the projection
+func (w *workerServer) GetProjectedScatter(ctx context.Context, basis *pb.Matrix) (*pb.Matrix, error) {
+	if w.matrix == nil {
+		return nil, errors.New("No matrix available")
+	}
+
+	q := toDense(basis)
+	if q.Rows() != w.matrix.Cols() {
+		return nil, errors.New("Inconsistent basis matrix size")
+	}
+
+	b, err := w.matrix.TimesDense(q)
+	if err != nil {
+		return nil, err
+	}
+
+	scatter, err := b.Transpose().TimesDense(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProto(scatter), nil
+}
+
+// GetTriangularFactor returns the R factor of the QR decomposition of the
+// matrix. Stacking these factors across workers and decomposing again yields
+// the R factor of the full dataset without forming the scatter matrix. When
+// there are fewer rows than columns the rows themselves are returned since
+// they reduce to the same factor
+func (w *workerServer) GetTriangularFactor(ctx context.Context, unit *pb.Unit) (*pb.Matrix, error) {
+	if w.matrix == nil {
+		return nil, errors.New("No matrix available")
+	}
+
+	if w.matrix.Rows() < w.matrix.Cols() {
+		return toProto(w.matrix), nil
+	}
+
+	_, r, err := linalg.QR(w.matrix)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProto(r), nil
+}
+
+// GetLocalComponents computes the top k principal components of the matrix
+// and returns them as rows, each scaled by its singular value. The scaled rows
+// from every worker can be stacked and decomposed again to approximate the
+// components of the full dataset
+func (w *workerServer) GetLocalComponents(ctx context.Context, components *pb.Components) (*pb.Matrix, error) {
+	if w.matrix == nil {
+		return nil, errors.New("No matrix available")
+	}
+
+	numRows, numCols := w.matrix.GetSize()
+	k := int(components.K)
+	if k < 1 || k > numCols {
+		return nil, errors.New("Invalid number of components")
+	}
+
+	var vectors *matrix.DenseMatrix
+	var values []float64
+	var err error
+	if numRows >= numCols {
+		_, values, vectors, err = linalg.SVD(w.matrix)
+	} else {
+		vectors, values, _, err = linalg.SVD(w.matrix.Transpose())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if k > len(values) {
+		k = len(values)
+	}
+
+	scaled := make([]*pb.Vector, k)
+	for j := range scaled {
+		elements := make([]float64, numCols)
+		for i := range elements {
+			elements[i] = values[j] * vectors.Get(i, j)
+		}
+		scaled[j] = &pb.Vector{
+			Elements: elements,
 		}
+	}
 
-		answer, err := strconv.ParseFloat(row[0], 64)
+	return &pb.Matrix{Elements: scaled}, nil
+}
+
+// GetRowBlock returns the rows of the matrix. The coordinator uses this to
+// form the inner products between rows when there are fewer rows than
+// columns
+func (w *workerServer) GetRowBlock(ctx context.Context, unit *pb.Unit) (*pb.Matrix, error) {
+	if w.matrix == nil {
+		return nil, errors.New("No matrix available")
+	}
+
+	return toProto(w.matrix), nil
+}
+
+// GetRobustMoments weights each standardized row by how far it lies from the
+// given center. Rows whose Mahalanobis distance under the given precision
+// matrix exceeds the cutoff are down-weighted by the squared ratio of the
+// cutoff to their distance; a cutoff of zero leaves every row at full weight.
+// The robust weights are kept for GetRobustWeights and the weighted sum and
+// scatter matrix about the center are returned
+func (w *workerServer) GetRobustMoments(ctx context.Context, estimate *pb.RobustEstimate) (*pb.Moments, error) {
+	if w.raw == nil {
+		return nil, errors.New("No matrix available")
+	}
+	cols := w.raw.Cols()
+	if len(estimate.Center.Elements) != cols || len(estimate.Precision.Elements) != cols {
+		return nil, errors.New("Inconsistent vector sizes")
+	}
+
+	center := matrix.MakeDenseMatrix(estimate.Center.Elements, 1, cols)
+	precision := toDense(estimate.Precision)
+	cutoff := estimate.Cutoff * estimate.Cutoff
+
+	w.robust = make([]float64, w.raw.Rows())
+	sum := make([]float64, cols)
+	scatter := matrix.Zeros(cols, cols)
+	var weight float64
+	for i := range w.robust {
+		row := w.standardizedRow(i)
+		centered, err := row.MinusDense(center)
+		if err != nil {
+			return nil, err
+		}
+		product, err := centered.TimesDense(precision)
+		if err != nil {
+			return nil, err
+		}
+		distance, err := product.TimesDense(centered.Transpose())
 		if err != nil {
 			return nil, err
 		}
 
-		answers = append(answers, answer)
+		w.robust[i] = 1
+		if d := distance.Get(0, 0); cutoff > 0 && d > cutoff {
+			w.robust[i] = cutoff / d
+		}
+		scale := rowWeight(w.weights, i) * w.robust[i]
+		weight += scale
+		for j := 0; j < cols; j++ {
+			sum[j] += scale * row.Get(0, j)
+			for k := 0; k < cols; k++ {
+				scatter.Set(j, k, scatter.Get(j, k)+scale*centered.Get(0, j)*centered.Get(0, k))
+			}
+		}
 	}
 
-	if len(answers) != len(vectors) {
-		return nil, errors.New("Inconsistent answer and vector sizes")
+	moments := &pb.Moments{
+		Rows:    int32(w.raw.Rows()),
+		Sum:     &pb.Vector{Elements: sum},
+		Scatter: toProto(scatter),
+		Weight:  weight,
 	}
+	return moments, nil
+}
 
-	filename := fmt.Sprintf("data/projected-%s", w.filename)
-	out, err := os.Create(filename)
-	if err != nil {
-		return nil, err
+// GetRobustWeights returns the robust weight of each row from the last call to
+// GetRobustMoments
+func (w *workerServer) GetRobustWeights(ctx context.Context, unit *pb.Unit) (*pb.Vector, error) {
+	if w.robust == nil {
+		return nil, errors.New("No robust weights available")
 	}
-	defer out.Close()
 
-	var values []string
-	wr := csv.NewWriter(out)
-	for i := range answers {
-		vectors[i] = append(vectors[i], answers[i])
+	return &pb.Vector{Elements: w.robust}, nil
+}
 
-		values = []string{}
-		for _, value := range vectors[i] {
-			values = append(values, strconv.FormatFloat(value, 'E', -1, 32))
+// GetObservedMoments returns the weighted count of the observed entries in
+// each column along with their weighted mean and the weighted sum of their
+// squared deviations from that mean, one row each. Missing entries are
+// skipped so that the coordinator can standardize incomplete data
+func (w *workerServer) GetObservedMoments(ctx context.Context, unit *pb.Unit) (*pb.Matrix, error) {
+	if w.raw == nil {
+		return nil, errors.New("No matrix available")
+	}
+	rows, cols := w.raw.GetSize()
+
+	weight := make([]float64, cols)
+	mean := make([]float64, cols)
+	squares := make([]float64, cols)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			x := w.raw.Get(i, j)
+			if math.IsNaN(x) {
+				continue
+			}
+			weight[j] += rowWeight(w.weights, i)
+			mean[j] += rowWeight(w.weights, i) * x
 		}
-		if err = wr.Write(values); err != nil {
-			return nil, err
+	}
+	for j := range mean {
+		if weight[j] > 0 {
+			mean[j] /= weight[j]
 		}
 	}
-	wr.Flush()
-	if err = wr.Error(); err != nil {
-		return nil, err
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			x := w.raw.Get(i, j)
+			if math.IsNaN(x) {
+				continue
+			}
+			squares[j] += rowWeight(w.weights, i) * (x - mean[j]) * (x - mean[j])
+		}
 	}
 
-	return &pb.DataFile{Name: filename}, nil
+	moments := &pb.Matrix{
+		Elements: []*pb.Vector{
+			&pb.Vector{Elements: weight},
+			&pb.Vector{Elements: mean},
+			&pb.Vector{Elements: squares},
+		},
+	}
+	return moments, nil
+}
+
+// GetLatentStatistics runs the expectation step of probabilistic PCA on the
+// standardized rows. For each row the posterior mean E[z] and second moment
+// E[zz^T] of the latent variables are computed from its observed entries only.
+// The weighted sums of y_j E[z] and E[zz^T] over the rows observing each column
+// are returned as one row per column, along with the weighted sum of squares
+// and count of the observed entries and the log-likelihood of the model
+func (w *workerServer) GetLatentStatistics(ctx context.Context, model *pb.LatentModel) (*pb.LatentStatistics, error) {
+	if w.raw == nil {
+		return nil, errors.New("No matrix available")
+	}
+	cols := w.raw.Cols()
+	if len(model.Loadings.Elements) != cols || model.Noise <= 0 {
+		return nil, errors.New("Invalid latent model")
+	}
+	loadings := toDense(model.Loadings)
+	k := loadings.Cols()
+	noise := model.Noise
+
+	products := matrix.Zeros(cols, k)
+	moments := matrix.Zeros(cols, k*k)
+	var squares, observed, logLikelihood float64
+	for i := 0; i < w.raw.Rows(); i++ {
+		row := w.standardizedRow(i)
+		indices := []int{}
+		for j := 0; j < cols; j++ {
+			if !math.IsNaN(row.Get(0, j)) {
+				indices = append(indices, j)
+			}
+		}
+		if len(indices) == 0 {
+			continue
+		}
+
+		// M = noise * I + W_o^T W_o and b = W_o^T y_o over the observed entries
+		precision := matrix.Eye(k)
+		precision.Scale(noise)
+		b := matrix.Zeros(k, 1)
+		var rowSquares float64
+		for _, j := range indices {
+			y := row.Get(0, j)
+			rowSquares += y * y
+			for p := 0; p < k; p++ {
+				b.Set(p, 0, b.Get(p, 0)+loadings.Get(j, p)*y)
+				for q := 0; q < k; q++ {
+					precision.Set(p, q, precision.Get(p, q)+loadings.Get(j, p)*loadings.Get(j, q))
+				}
+			}
+		}
+		inverse, err := linalg.PseudoInverse(precision)
+		if err != nil {
+			return nil, err
+		}
+		_, values, err := linalg.SymmetricEigen(precision)
+		if err != nil {
+			return nil, err
+		}
+		mean, err := inverse.TimesDense(b)
+		if err != nil {
+			return nil, err
+		}
+
+		weight := rowWeight(w.weights, i)
+		var logDet, fit float64
+		for _, value := range values {
+			logDet += math.Log(value)
+		}
+		for p := 0; p < k; p++ {
+			fit += b.Get(p, 0) * mean.Get(p, 0)
+		}
+		n := float64(len(indices))
+		logLikelihood -= weight * (n*math.Log(2*math.Pi) + (n-float64(k))*math.Log(noise) + logDet + (rowSquares-fit)/noise) / 2
+		squares += weight * rowSquares
+		observed += weight * n
+
+		for _, j := range indices {
+			y := row.Get(0, j)
+			for p := 0; p < k; p++ {
+				products.Set(j, p, products.Get(j, p)+weight*y*mean.Get(p, 0))
+				for q := 0; q < k; q++ {
+					second := noise*inverse.Get(p, q) + mean.Get(p, 0)*mean.Get(q, 0)
+					moments.Set(j, p*k+q, moments.Get(j, p*k+q)+weight*second)
+				}
+			}
+		}
+	}
+
+	statistics := &pb.LatentStatistics{
+		Products:      toProto(products),
+		Moments:       toProto(moments),
+		Squares:       squares,
+		Observed:      observed,
+		LogLikelihood: logLikelihood,
+	}
+	return statistics, nil
+}
+
+// GetPermutedScatter shuffles each column of the standardized matrix
+// independently with the given seed and returns the scatter matrix of the
+// result. Shuffling destroys the correlations between columns while keeping
+// their distributions, which gives the eigenvalues expected by chance. The
+// standardized values are shuffled without their weights, and each row of the
+// result takes the weight of the row it replaces, so that the weights stay
+// with their rows
+func (w *workerServer) GetPermutedScatter(ctx context.Context, permutation *pb.Permutation) (*pb.Matrix, error) {
+	if w.raw == nil {
+		return nil, errors.New("No matrix available")
+	}
+	rows, cols := w.raw.GetSize()
+
+	standardized := make([]*matrix.DenseMatrix, rows)
+	for i := range standardized {
+		standardized[i] = w.standardizedRow(i)
+	}
+
+	r := rand.New(rand.NewSource(permutation.Seed))
+	permuted := matrix.Zeros(rows, cols)
+	for j := 0; j < cols; j++ {
+		for i, k := range r.Perm(rows) {
+			permuted.Set(i, j, math.Sqrt(rowWeight(w.weights, i))*standardized[k].Get(0, j))
+		}
+	}
+
+	scatter, err := permuted.Transpose().TimesDense(permuted)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProto(scatter), nil
+}
+
+// GetBootstrapMoments draws as many rows as the partition holds, with
+// replacement and using the given seed, and returns the number and total
+// weight of the drawn rows along with their weighted sum and their weighted
+// scatter matrix about their own mean, all standardized with the last mean
+// and standard deviation
+func (w *workerServer) GetBootstrapMoments(ctx context.Context, resample *pb.Resample) (*pb.Moments, error) {
+	if w.raw == nil {
+		return nil, errors.New("No matrix available")
+	}
+	rows, cols := w.raw.GetSize()
+
+	r := rand.New(rand.NewSource(resample.Seed))
+	drawn := make([]int, rows)
+	for i := range drawn {
+		drawn[i] = r.Intn(rows)
+	}
+
+	var weight float64
+	sum := make([]float64, cols)
+	for _, i := range drawn {
+		row := w.standardizedRow(i)
+		weight += rowWeight(w.weights, i)
+		for j := 0; j < cols; j++ {
+			sum[j] += rowWeight(w.weights, i) * row.Get(0, j)
+		}
+	}
+
+	scatter := matrix.Zeros(cols, cols)
+	if weight > 0 {
+		centered := matrix.Zeros(rows, cols)
+		for k, i := range drawn {
+			row := w.standardizedRow(i)
+			scale := math.Sqrt(rowWeight(w.weights, i))
+			for j := 0; j < cols; j++ {
+				centered.Set(k, j, scale*(row.Get(0, j)-sum[j]/weight))
+			}
+		}
+		var err error
+		scatter, err = centered.Transpose().TimesDense(centered)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	moments := &pb.Moments{
+		Rows:    int32(rows),
+		Sum:     &pb.Vector{Elements: sum},
+		Scatter: toProto(scatter),
+		Weight:  weight,
+	}
+	return moments, nil
+}
+
+// GetTrainingMoments returns the number and total weight of the rows outside
+// the given fold along with their weighted sum and their weighted scatter
+// matrix about their own mean, all standardized with the last mean and
+// standard deviation. Row i belongs to fold i modulo the number of folds
+func (w *workerServer) GetTrainingMoments(ctx context.Context, fold *pb.Fold) (*pb.Moments, error) {
+	if w.raw == nil {
+		return nil, errors.New("No matrix available")
+	}
+	if fold.Count < 2 || fold.Index < 0 || fold.Index >= fold.Count {
+		return nil, errors.New("Invalid fold")
+	}
+
+	training := []int{}
+	for i := 0; i < w.raw.Rows(); i++ {
+		if !inFold(i, fold) {
+			training = append(training, i)
+		}
+	}
+	return w.subsetMoments(training)
+}
+
+// GetReconstructionError projects each standardized row in the given fold
+// onto the leading components, after subtracting the center, and returns the
+// weighted sum of squared reconstruction errors when keeping the first k
+// components, for each k from one to the number of components
+func (w *workerServer) GetReconstructionError(ctx context.Context, reconstruction *pb.Reconstruction) (*pb.Vector, error) {
+	if w.raw == nil {
+		return nil, errors.New("No matrix available")
+	}
+	fold := reconstruction.Fold
+	if fold.Count < 2 || fold.Index < 0 || fold.Index >= fold.Count {
+		return nil, errors.New("Invalid fold")
+	}
+	cols := w.raw.Cols()
+	if len(reconstruction.Center.Elements) != cols {
+		return nil, errors.New("Inconsistent vector sizes")
+	}
+	components := toDense(reconstruction.Components)
+
+	press := make([]float64, components.Rows())
+	for i := 0; i < w.raw.Rows(); i++ {
+		if !inFold(i, fold) {
+			continue
+		}
+		row := w.standardizedRow(i)
+		centered := matrix.Zeros(cols, 1)
+		var residual float64
+		for j := 0; j < cols; j++ {
+			centered.Set(j, 0, row.Get(0, j)-reconstruction.Center.Elements[j])
+			residual += centered.Get(j, 0) * centered.Get(j, 0)
+		}
+		scores, err := components.TimesDense(centered)
+		if err != nil {
+			return nil, err
+		}
+		for k := range press {
+			residual -= scores.Get(k, 0) * scores.Get(k, 0)
+			press[k] += rowWeight(w.weights, i) * math.Max(residual, 0)
+		}
+	}
+
+	return &pb.Vector{Elements: press}, nil
+}
+
+// SampleCentroids draws candidate centroids for k-means|| initialization.
+// Each row is kept independently with probability equal to the factor times
+// its weight times its squared distance to the nearest given centroid, or to
+// the factor times its weight if there are no centroids yet
+func (w *workerServer) SampleCentroids(ctx context.Context, clustering *pb.Clustering) (*pb.Matrix, error) {
+	if w.raw == nil {
+		return nil, errors.New("No matrix available")
+	}
+	centroids := toDense(clustering.Centroids)
+	projection := toDense(clustering.Projection)
+
+	r := rand.New(rand.NewSource(clustering.Seed))
+	sampled := []*pb.Vector{}
+	for i := 0; i < w.raw.Rows(); i++ {
+		point, err := w.clusterPoint(i, projection)
+		if err != nil {
+			return nil, err
+		}
+		distance := 1.0
+		if centroids.Rows() > 0 {
+			_, distance, err = nearestCentroid(point, centroids)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if r.Float64() < clustering.Factor*rowWeight(w.weights, i)*distance {
+			sampled = append(sampled, &pb.Vector{Elements: point.Array()})
+		}
+	}
+
+	return &pb.Matrix{Elements: sampled}, nil
+}
+
+// AssignClusters assigns each row to its nearest centroid and returns the
+// weighted sum of the rows and the total weight assigned to each centroid
+// along with the weighted sum of squared distances to the nearest centroids.
+// The assignments are kept for ComputeScores
+func (w *workerServer) AssignClusters(ctx context.Context, clustering *pb.Clustering) (*pb.ClusterSums, error) {
+	if w.raw == nil {
+		return nil, errors.New("No matrix available")
+	}
+	centroids := toDense(clustering.Centroids)
+	if centroids.Rows() == 0 {
+		return nil, errors.New("No centroids")
+	}
+	projection := toDense(clustering.Projection)
+
+	w.clusters = make([]int, w.raw.Rows())
+	sums := matrix.Zeros(centroids.Rows(), centroids.Cols())
+	weights := make([]float64, centroids.Rows())
+	var cost float64
+	for i := range w.clusters {
+		point, err := w.clusterPoint(i, projection)
+		if err != nil {
+			return nil, err
+		}
+		nearest, distance, err := nearestCentroid(point, centroids)
+		if err != nil {
+			return nil, err
+		}
+		w.clusters[i] = nearest
+		weight := rowWeight(w.weights, i)
+		weights[nearest] += weight
+		cost += weight * distance
+		for j := 0; j < point.Cols(); j++ {
+			sums.Set(nearest, j, sums.Get(nearest, j)+weight*point.Get(0, j))
+		}
+	}
+
+	clusterSums := &pb.ClusterSums{
+		Sums:    toProto(sums),
+		Weights: &pb.Vector{Elements: weights},
+		Cost:    cost,
+	}
+	return clusterSums, nil
+}
+
+// GetResiduals predicts the target column of each raw row from the other
+// columns with the linear model and returns the total weight of the rows
+// along with the weighted sums of the residuals, their squares and their
+// absolute values and the smallest and largest residuals
+func (w *workerServer) GetResiduals(ctx context.Context, model *pb.LinearModel) (*pb.ResidualSummary, error) {
+	if w.raw == nil {
+		return nil, errors.New("No matrix available")
+	}
+	cols := w.raw.Cols()
+	if model.Target < 0 || int(model.Target) >= cols || len(model.Coefficients.Elements) != cols {
+		return nil, errors.New("Inconsistent vector sizes")
+	}
+
+	summary := &pb.ResidualSummary{
+		Min: math.Inf(1),
+		Max: math.Inf(-1),
+	}
+	for i := 0; i < w.raw.Rows(); i++ {
+		residual := w.raw.Get(i, int(model.Target)) - model.Intercept
+		for j, coefficient := range model.Coefficients.Elements {
+			if j != int(model.Target) {
+				residual -= coefficient * w.raw.Get(i, j)
+			}
+		}
+		weight := rowWeight(w.weights, i)
+		summary.Weight += weight
+		summary.Sum += weight * residual
+		summary.Squares += weight * residual * residual
+		summary.Absolute += weight * math.Abs(residual)
+		summary.Min = math.Min(summary.Min, residual)
+		summary.Max = math.Max(summary.Max, residual)
+	}
+
+	return summary, nil
+}
+
+// GetClassMoments groups the standardized rows by their class and returns the
+// weighted sum and the weighted scatter matrix about the class mean of each
+// class, with the classes in ascending order
+func (w *workerServer) GetClassMoments(ctx context.Context, unit *pb.Unit) (*pb.ClassMoments, error) {
+	if w.raw == nil {
+		return nil, errors.New("No matrix available")
+	}
+	answers, err := w.answers()
+	if err != nil {
+		return nil, err
+	}
+
+	members := map[float64][]int{}
+	for i, answer := range answers {
+		members[answer] = append(members[answer], i)
+	}
+	classes := &pb.ClassMoments{}
+	for label := range members {
+		classes.Labels = append(classes.Labels, label)
+	}
+	sort.Float64s(classes.Labels)
+	for _, label := range classes.Labels {
+		moments, err := w.subsetMoments(members[label])
+		if err != nil {
+			return nil, err
+		}
+		classes.Moments = append(classes.Moments, moments)
+	}
+
+	return classes, nil
+}
+
+// GetLabeledScores projects each standardized row onto the rows of the given
+// matrix and returns the projections with the class of the row appended
+func (w *workerServer) GetLabeledScores(ctx context.Context, directions *pb.Matrix) (*pb.Matrix, error) {
+	if w.raw == nil {
+		return nil, errors.New("No matrix available")
+	}
+	p := toDense(directions)
+	if p.Cols() != w.raw.Cols() {
+		return nil, errors.New("Inconsistent vector sizes")
+	}
+	answers, err := w.answers()
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]*pb.Vector, len(answers))
+	for i := range scores {
+		score, err := p.TimesDense(w.standardizedRow(i).Transpose())
+		if err != nil {
+			return nil, err
+		}
+		scores[i] = &pb.Vector{Elements: append(score.Transpose().Array(), answers[i])}
+	}
+
+	return &pb.Matrix{Elements: scores}, nil
+}
+
+// GetGroupScatter returns the weighted scatter matrix of the standardized
+// columns of the first group followed by those of the second, which holds the
+// scatter matrix of each group and the cross scatter between them as blocks
+func (w *workerServer) GetGroupScatter(ctx context.Context, groups *pb.ColumnGroups) (*pb.Matrix, error) {
+	if w.matrix == nil {
+		return nil, errors.New("No matrix available")
+	}
+	columns := append(append([]int32{}, groups.First...), groups.Second...)
+	for _, j := range columns {
+		if j < 0 || int(j) >= w.matrix.Cols() {
+			return nil, errors.New("Invalid column")
+		}
+	}
+
+	selected := matrix.Zeros(w.matrix.Rows(), len(columns))
+	for i := 0; i < w.matrix.Rows(); i++ {
+		for k, j := range columns {
+			selected.Set(i, k, w.matrix.Get(i, int(j)))
+		}
+	}
+	scatter, err := selected.Transpose().TimesDense(selected)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProto(scatter), nil
+}
+
+// GetIndependentStatistics whitens each standardized row and returns the
+// weighted sums over the rows of tanh(y) z^T and of 1 - tanh(y)^2, where z is
+// the whitened row and y = W z its projection onto the current unmixing
+// matrix W, along with the total weight of the rows. These are the
+// expectations a FastICA update with the log cosh contrast needs
+func (w *workerServer) GetIndependentStatistics(ctx context.Context, model *pb.IndependentModel) (*pb.IndependentStatistics, error) {
+	if w.raw == nil {
+		return nil, errors.New("No matrix available")
+	}
+	whitening := toDense(model.Whitening)
+	unmixing := toDense(model.Unmixing)
+	if whitening.Cols() != w.raw.Cols() || unmixing.Cols() != whitening.Rows() {
+		return nil, errors.New("Inconsistent vector sizes")
+	}
+	k := unmixing.Rows()
+
+	products := matrix.Zeros(k, whitening.Rows())
+	derivatives := make([]float64, k)
+	var total float64
+	for i := 0; i < w.raw.Rows(); i++ {
+		z, err := whitening.TimesDense(w.standardizedRow(i).Transpose())
+		if err != nil {
+			return nil, err
+		}
+		y, err := unmixing.TimesDense(z)
+		if err != nil {
+			return nil, err
+		}
+		weight := rowWeight(w.weights, i)
+		total += weight
+		for c := 0; c < k; c++ {
+			g := math.Tanh(y.Get(c, 0))
+			derivatives[c] += weight * (1 - g*g)
+			for j := 0; j < z.Rows(); j++ {
+				products.Set(c, j, products.Get(c, j)+weight*g*z.Get(j, 0))
+			}
+		}
+	}
+
+	statistics := &pb.IndependentStatistics{
+		Products:    toProto(products),
+		Derivatives: &pb.Vector{Elements: derivatives},
+		Weight:      total,
+	}
+	return statistics, nil
+}
+
+// GetColumnStatistics returns, for each raw column, the number of missing
+// entries and, over the observed entries, their total weight, smallest and
+// largest values, weighted mean and weighted sums of the second, third and
+// fourth powers of the deviations from that mean. Central sums from different
+// partitions can be merged exactly, unlike raw power sums, which lose
+// precision when the mean is large relative to the spread
+func (w *workerServer) GetColumnStatistics(ctx context.Context, unit *pb.Unit) (*pb.ColumnStatistics, error) {
+	if w.raw == nil {
+		return nil, errors.New("No matrix available")
+	}
+	rows, cols := w.raw.GetSize()
+
+	statistics := &pb.ColumnStatistics{
+		Weight:  make([]float64, cols),
+		Missing: make([]int64, cols),
+		Min:     make([]float64, cols),
+		Max:     make([]float64, cols),
+		Mean:    make([]float64, cols),
+		M2:      make([]float64, cols),
+		M3:      make([]float64, cols),
+		M4:      make([]float64, cols),
+	}
+	for j := 0; j < cols; j++ {
+		statistics.Min[j] = math.Inf(1)
+		statistics.Max[j] = math.Inf(-1)
+		for i := 0; i < rows; i++ {
+			x := w.raw.Get(i, j)
+			if math.IsNaN(x) {
+				statistics.Missing[j]++
+				continue
+			}
+			statistics.Weight[j] += rowWeight(w.weights, i)
+			statistics.Mean[j] += rowWeight(w.weights, i) * x
+			statistics.Min[j] = math.Min(statistics.Min[j], x)
+			statistics.Max[j] = math.Max(statistics.Max[j], x)
+		}
+		if statistics.Weight[j] <= 0 {
+			continue
+		}
+		statistics.Mean[j] /= statistics.Weight[j]
+		for i := 0; i < rows; i++ {
+			x := w.raw.Get(i, j)
+			if math.IsNaN(x) {
+				continue
+			}
+			d := x - statistics.Mean[j]
+			statistics.M2[j] += rowWeight(w.weights, i) * d * d
+			statistics.M3[j] += rowWeight(w.weights, i) * d * d * d
+			statistics.M4[j] += rowWeight(w.weights, i) * d * d * d * d
+		}
+	}
+
+	return statistics, nil
+}
+
+// GetColumnSketches summarizes the observed entries of each raw column with a
+// t-digest of the given compression and with a weighted histogram of equal
+// width bins between the given smallest and largest values. Entries outside
+// that range are counted in the first or last bin
+func (w *workerServer) GetColumnSketches(ctx context.Context, binning *pb.Binning) (*pb.ColumnSketches, error) {
+	if w.raw == nil {
+		return nil, errors.New("No matrix available")
+	}
+	rows, cols := w.raw.GetSize()
+	if binning.Bins < 1 || len(binning.Min.Elements) != cols || len(binning.Max.Elements) != cols {
+		return nil, errors.New("Invalid binning")
+	}
+	bins := int(binning.Bins)
+
+	sketches := &pb.ColumnSketches{}
+	histograms := matrix.Zeros(cols, bins)
+	for j := 0; j < cols; j++ {
+		digest := sketch.New(binning.Compression)
+		low := binning.Min.Elements[j]
+		width := (binning.Max.Elements[j] - low) / float64(bins)
+		for i := 0; i < rows; i++ {
+			x := w.raw.Get(i, j)
+			if math.IsNaN(x) {
+				continue
+			}
+			weight := rowWeight(w.weights, i)
+			digest.Add(x, weight)
+
+			bin := 0
+			if width > 0 {
+				bin = int(math.Max(0, math.Min(float64(bins-1), math.Floor((x-low)/width))))
+			}
+			histograms.Set(j, bin, histograms.Get(j, bin)+weight)
+		}
+
+		means, weights := digest.Centroids()
+		sketches.Digests = append(sketches.Digests, &pb.Digest{
+			Means:   means,
+			Weights: weights,
+			Min:     digest.Min(),
+			Max:     digest.Max(),
+		})
+	}
+	sketches.Histograms = toProto(histograms)
+
+	return sketches, nil
+}
+
+// ComputeScores receives a matrix of top principal component vectors and
+// projects its rows onto that subspace before returning the projection along
+// with the classifiation of each row and, if the rows were clustered, the
+// cluster of each row
+func (w *workerServer) ComputeScores(ctx context.Context, top *pb.Matrix) (*pb.DataFile, error) {
+	k := len(top.Elements)
+	topVectors := make([][]float64, k)
+	for i := range topVectors {
+		topVectors[i] = top.Elements[i].Elements
+	}
+	p := matrix.MakeDenseMatrixStacked(topVectors)
+
+	vectors := make([][]float64, w.matrix.Rows())
+	for i := range vectors {
+		vector, err := p.TimesDense(w.standardizedRow(i).Transpose())
+		if err != nil {
+			return nil, err
+		}
+		vectors[i] = vector.Transpose().Array()
+	}
+
+	answers, err := w.answers()
+	if err != nil {
+		return nil, err
+	}
+
+	filename := fmt.Sprintf("data/projected-%s", w.filename)
+	out, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer out.Close()
+
+	var values []string
+	wr := csv.NewWriter(out)
+	for i := range answers {
+		vectors[i] = append(vectors[i], answers[i])
+		if w.clusters != nil {
+			vectors[i] = append(vectors[i], float64(w.clusters[i]))
+		}
+
+		values = []string{}
+		for _, value := range vectors[i] {
+			values = append(values, strconv.FormatFloat(value, 'E', -1, 32))
+		}
+		if err = wr.Write(values); err != nil {
+			return nil, err
+		}
+	}
+	wr.Flush()
+	if err = wr.Error(); err != nil {
+		return nil, err
+	}
+
+	return &pb.DataFile{Name: filename}, nil
+}
+
+// standardize subtracts the mean from each element of the loaded data and
+// divides the result by the standard deviation of its column. When the rows
+// are weighted each row is then multiplied by the square root of its weight so
+// that every product of the transposed matrix with itself is weighted
+func (w *workerServer) standardize(meanAndSD *pb.Matrix) error {
+	if w.raw == nil {
+		return errors.New("No matrix available")
+	}
+	if len(meanAndSD.Elements) != 2 {
+		return errors.New("Invalid matrix. Need mean and standard deviation rows.")
+	}
+
+	mean := meanAndSD.Elements[0]
+	sd := meanAndSD.Elements[1]
+
+	numRows, numCols := w.raw.GetSize()
+
+	rows := make([][]float64, numRows)
+	for i := range rows {
+		rows[i] = mean.Elements
+	}
+
+	meanMatrix := matrix.MakeDenseMatrixStacked(rows)
+
+	w.matrix = w.raw.Copy()
+	err := w.matrix.SubtractDense(meanMatrix)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < numRows; i++ {
+		scale := math.Sqrt(rowWeight(w.weights, i))
+		for j := 0; j < numCols; j++ {
+			val := w.matrix.Get(i, j)
+			w.matrix.Set(i, j, scale*val/sd.Elements[j])
+		}
+	}
+	w.mean = mean.Elements
+	w.sd = sd.Elements
+
+	return nil
+}
+
+// standardizedRow returns a copy of row i of the loaded data standardized
+// with the last mean and standard deviation but without its weight applied.
+// GetRowVector shares storage with the raw data, so the row is copied before
+// it is standardized and callers are free to modify it
+func (w *workerServer) standardizedRow(i int) *matrix.DenseMatrix {
+	row := w.raw.GetRowVector(i).Copy()
+	if w.mean == nil {
+		return row
+	}
+	for j := 0; j < row.Cols(); j++ {
+		row.Set(0, j, (row.Get(0, j)-w.mean[j])/w.sd[j])
+	}
+	return row
+}
+
+// clusterPoint returns row i standardized and, if the projection has rows,
+// projected onto them
+func (w *workerServer) clusterPoint(i int, projection *matrix.DenseMatrix) (*matrix.DenseMatrix, error) {
+	row := w.standardizedRow(i)
+	if projection.Rows() == 0 {
+		return row, nil
+	}
+	projected, err := projection.TimesDense(row.Transpose())
+	if err != nil {
+		return nil, err
+	}
+	return projected.Transpose(), nil
+}
+
+// nearestCentroid returns the index of the centroid nearest to the point
+// along with the squared distance between them
+func nearestCentroid(point *matrix.DenseMatrix, centroids *matrix.DenseMatrix) (int, float64, error) {
+	if point.Cols() != centroids.Cols() {
+		return 0, 0, errors.New("Inconsistent vector sizes")
+	}
+	nearest := 0
+	best := math.Inf(1)
+	for c := 0; c < centroids.Rows(); c++ {
+		var distance float64
+		for j := 0; j < point.Cols(); j++ {
+			d := point.Get(0, j) - centroids.Get(c, j)
+			distance += d * d
+		}
+		if distance < best {
+			nearest = c
+			best = distance
+		}
+	}
+	return nearest, best, nil
+}
+
+// subsetMoments returns the weighted sum of the given standardized rows and
+// their weighted scatter matrix about their own weighted mean
+func (w *workerServer) subsetMoments(rows []int) (*pb.Moments, error) {
+	cols := w.raw.Cols()
+	var weight float64
+	sum := make([]float64, cols)
+	for _, i := range rows {
+		row := w.standardizedRow(i)
+		weight += rowWeight(w.weights, i)
+		for j := 0; j < cols; j++ {
+			sum[j] += rowWeight(w.weights, i) * row.Get(0, j)
+		}
+	}
+
+	scatter := matrix.Zeros(cols, cols)
+	if weight > 0 {
+		centered := matrix.Zeros(len(rows), cols)
+		for k, i := range rows {
+			row := w.standardizedRow(i)
+			scale := math.Sqrt(rowWeight(w.weights, i))
+			for j := 0; j < cols; j++ {
+				centered.Set(k, j, scale*(row.Get(0, j)-sum[j]/weight))
+			}
+		}
+		var err error
+		scatter, err = centered.Transpose().TimesDense(centered)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	moments := &pb.Moments{
+		Rows:    int32(len(rows)),
+		Sum:     &pb.Vector{Elements: sum},
+		Scatter: toProto(scatter),
+		Weight:  weight,
+	}
+	return moments, nil
+}
+
+// answers reads the class of each loaded row, including appended rows, from
+// the answers files of the loaded data files
+func (w *workerServer) answers() ([]float64, error) {
+	answers, err := readAnswers(w.filename)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range w.appended {
+		appended, err := readAnswers(name)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, appended...)
+	}
+
+	if len(answers) != w.raw.Rows() {
+		return nil, errors.New("Inconsistent answer and vector sizes")
+	}
+	return answers, nil
+}
+
+// inFold returns whether row i belongs to the given fold
+func inFold(i int, fold *pb.Fold) bool {
+	return int32(i)%fold.Count == fold.Index
+}
+
+// splitWeights removes the weight column described by the data file from each
+// row and returns the remaining rows along with the weights. The weights are
+// nil if the data file is unweighted
+func splitWeights(vectors [][]float64, file *pb.DataFile) ([][]float64, []float64, error) {
+	if !file.Weighted {
+		return vectors, nil, nil
+	}
+
+	column := int(file.WeightColumn)
+	weights := make([]float64, len(vectors))
+	rows := make([][]float64, len(vectors))
+	for i, vector := range vectors {
+		if column < 0 || column >= len(vector) {
+			return nil, nil, errors.New("Invalid weight column")
+		}
+		if math.IsNaN(vector[column]) {
+			return nil, nil, errors.New("Missing row weight")
+		}
+		if vector[column] < 0 {
+			return nil, nil, errors.New("Negative row weight")
+		}
+		weights[i] = vector[column]
+		rows[i] = append(append([]float64{}, vector[:column]...), vector[column+1:]...)
+	}
+
+	return rows, weights, nil
+}
+
+// rowWeight returns the weight of row i, which is 1 for unweighted data
+func rowWeight(weights []float64, i int) float64 {
+	if weights == nil {
+		return 1
+	}
+	return weights[i]
+}
+
+// totalWeight returns the sum of the weights, which is the number of rows for
+// unweighted data
+func totalWeight(weights []float64, rows int) float64 {
+	if weights == nil {
+		return float64(rows)
+	}
+	var total float64
+	for _, weight := range weights {
+		total += weight
+	}
+	return total
+}
+
+// countMissing returns the number of missing values in the rows
+func countMissing(vectors [][]float64) int64 {
+	var missing int64
+	for _, vector := range vectors {
+		for _, x := range vector {
+			if math.IsNaN(x) {
+				missing++
+			}
+		}
+	}
+	return missing
+}
+
+// readMatrix reads the rows of a CSV file in the data directory and returns
+// them along with the number of columns. Empty and NA fields are missing
+// values and are read as NaN
+func readMatrix(name string) ([][]float64, int, error) {
+	cols := 0
+	vectors := [][]float64{}
+
+	f, err := os.Open(fmt.Sprintf("data/%s", name))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(bufio.NewReader(f))
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+
+		num := len(row)
+		if cols == 0 {
+			cols = num
+		} else if num != cols {
+			return nil, 0, errors.New("Inconsistent vector sizes")
+		}
+
+		vector := make([]float64, num)
+		for i := range vector {
+			if row[i] == "" || row[i] == "NA" {
+				vector[i] = math.NaN()
+				continue
+			}
+			vector[i], err = strconv.ParseFloat(row[i], 64)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+
+		vectors = append(vectors, vector)
+	}
+
+	return vectors, cols, nil
+}
+
+// readAnswers reads the class of each row of a data file from the matching
+// answers file in the data directory
+func readAnswers(name string) ([]float64, error) {
+	in, err := os.Open(fmt.Sprintf("data/answers-%s", name))
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	answers := []float64{}
+	r := csv.NewReader(bufio.NewReader(in))
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if len(row) != 1 {
+			return nil, errors.New("Inconsistent answer vector size")
+		}
+
+		answer, err := strconv.ParseFloat(row[0], 64)
+		if err != nil {
+			return nil, err
+		}
+
+		answers = append(answers, answer)
+	}
+
+	return answers, nil
+}
+
+// toDense converts a protocol buffer matrix into a dense matrix. An empty
+// matrix converts to a matrix with no rows
+func toDense(m *pb.Matrix) *matrix.DenseMatrix {
+	if len(m.GetElements()) == 0 {
+		return matrix.Zeros(0, 0)
+	}
+	vectors := make([][]float64, len(m.Elements))
+	for i := range m.Elements {
+		vectors[i] = m.Elements[i].Elements
+	}
+	return matrix.MakeDenseMatrixStacked(vectors)
+}
+
+// toProto converts a dense matrix into a protocol buffer matrix
+func toProto(m *matrix.DenseMatrix) *pb.Matrix {
+	vectors := make([]*pb.Vector, m.Rows())
+	for i := range vectors {
+		vectors[i] = &pb.Vector{
+			Elements: m.GetRowVector(i).Array(),
+		}
+	}
+	return &pb.Matrix{
+		Elements: vectors,
+	}
 }
 
 func main() {
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+
+	matrix "github.com/skelterjohn/go.matrix"
+	pb "github.com/unchartedsoftware/rannu/cluster/rannu"
+)
+
+// testWorker returns a worker holding the given rows standardized with the
+// given mean and standard deviation
+func testWorker(rows [][]float64, mean []float64, sd []float64) *workerServer {
+	raw := matrix.MakeDenseMatrixStacked(rows)
+	return &workerServer{
+		raw:    raw,
+		matrix: raw.Copy(),
+		mean:   mean,
+		sd:     sd,
+	}
+}
+
+func TestStandardizedRowLeavesRawData(t *testing.T) {
+	tests := []struct {
+		name string
+		mean []float64
+		sd   []float64
+		want []float64
+	}{
+		{"standardized", []float64{2, 3}, []float64{1, 2}, []float64{-1, -0.5}},
+		{"not standardized", nil, nil, []float64{1, 2}},
+	}
+
+	for _, test := range tests {
+		w := testWorker([][]float64{{1, 2}, {3, 4}}, test.mean, test.sd)
+		for call := 0; call < 2; call++ {
+			row := w.standardizedRow(0)
+			for j, want := range test.want {
+				if row.Get(0, j) != want {
+					t.Errorf("%s: call %d has element %d %v, want %v", test.name, call, j, row.Get(0, j), want)
+				}
+			}
+			// callers may modify the row they are given
+			row.Set(0, 0, 100)
+		}
+		if w.raw.Get(0, 0) != 1 || w.raw.Get(0, 1) != 2 {
+			t.Errorf("%s: raw row changed to %v", test.name, w.raw.GetRowVector(0))
+		}
+	}
+}
+
+func TestReconstructionErrorLeavesRawData(t *testing.T) {
+	tests := []struct {
+		name string
+		mean []float64
+		sd   []float64
+	}{
+		{"standardized", []float64{1, 1}, []float64{2, 2}},
+		{"not standardized", nil, nil},
+	}
+
+	for _, test := range tests {
+		w := testWorker([][]float64{{1, 2}, {3, 4}, {5, 7}}, test.mean, test.sd)
+		reconstruction := &pb.Reconstruction{
+			Fold:       &pb.Fold{Index: 0, Count: 2},
+			Center:     &pb.Vector{Elements: []float64{1, 1}},
+			Components: &pb.Matrix{Elements: []*pb.Vector{{Elements: []float64{1, 0}}}},
+		}
+
+		var press []float64
+		for call := 0; call < 2; call++ {
+			result, err := w.GetReconstructionError(context.Background(), reconstruction)
+			if err != nil {
+				t.Fatalf("%s: unexpected error %v", test.name, err)
+			}
+			if call > 0 && result.Elements[0] != press[0] {
+				t.Errorf("%s: call %d returned %v, want %v", test.name, call, result.Elements, press)
+			}
+			press = result.Elements
+		}
+		if w.raw.Get(0, 1) != 2 || w.raw.Get(2, 1) != 7 {
+			t.Errorf("%s: raw data changed to %v", test.name, w.raw)
+		}
+	}
+}
+
+func TestTriangularFactorLeavesMatrix(t *testing.T) {
+	w := testWorker([][]float64{{1, 0}, {1, 1}, {1, 2}, {1, 3}}, nil, nil)
+	want := w.matrix.Copy()
+
+	var first *pb.Matrix
+	for call := 0; call < 2; call++ {
+		r, err := w.GetTriangularFactor(context.Background(), &pb.Unit{})
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if call > 0 && !matrix.Equals(toDense(r), toDense(first)) {
+			t.Errorf("call %d returned %v, want %v", call, r, first)
+		}
+		first = r
+	}
+	if !matrix.Equals(w.matrix, want) {
+		t.Errorf("matrix changed to %v, want %v", w.matrix, want)
+	}
+}
+
+func TestLocalComponentsLeavesMatrix(t *testing.T) {
+	w := testWorker([][]float64{{2, 0}, {1, 1}, {0, 3}}, nil, nil)
+	want := w.matrix.Copy()
+
+	var first *pb.Matrix
+	for call := 0; call < 2; call++ {
+		scaled, err := w.GetLocalComponents(context.Background(), &pb.Components{K: 2})
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if call > 0 && !matrix.Equals(toDense(scaled), toDense(first)) {
+			t.Errorf("call %d returned %v, want %v", call, scaled, first)
+		}
+		first = scaled
+	}
+	if !matrix.Equals(w.matrix, want) {
+		t.Errorf("matrix changed to %v, want %v", w.matrix, want)
+	}
+}
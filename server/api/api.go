@@ -20,6 +20,13 @@ func New(addrs []string) (http.Handler, error) {
 
 	mux := goji.NewMux()
 	mux.HandleFuncC(pat.Get("/api/pca/:dataset/:workers/:standardize"), pcaHandler)
+	mux.HandleFuncC(pat.Post("/api/pca/:dataset/:workers/:standardize/update/:update"), updateHandler)
+	mux.HandleFuncC(pat.Get("/api/regression/:dataset/:workers/:standardize"), regressionHandler)
+	mux.HandleFuncC(pat.Get("/api/lda/:dataset/:workers/:standardize"), ldaHandler)
+	mux.HandleFuncC(pat.Get("/api/cca/:dataset/:workers/:standardize"), ccaHandler)
+	mux.HandleFuncC(pat.Get("/api/ica/:dataset/:workers/:standardize"), icaHandler)
+	mux.HandleFuncC(pat.Get("/api/datasets/:name/stats"), statsHandler)
+	mux.HandleFuncC(pat.Get("/api/datasets/:name/correlation"), correlationHandler)
 
 	return mux, nil
 }
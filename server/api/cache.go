@@ -0,0 +1,39 @@
+package api
+
+import (
+	"log"
+	"sync"
+
+	q "github.com/unchartedsoftware/rannu/cluster/queue"
+)
+
+// datasetCache holds the responses of jobs that summarize a whole dataset,
+// which only change if its files are replaced
+type datasetCache struct {
+	sync.Mutex
+	responses map[string]*q.Response
+}
+
+func newDatasetCache() *datasetCache {
+	return &datasetCache{responses: map[string]*q.Response{}}
+}
+
+// get returns the cached response for the dataset, running the job for it
+// if there is none. Only successful responses are cached
+func (c *datasetCache) get(name string, job *q.Job) *q.Response {
+	c.Lock()
+	resp, ok := c.responses[name]
+	c.Unlock()
+	if ok {
+		return resp
+	}
+
+	log.Printf("Computing %s of %s", job.Analysis, name)
+	resp = awaitJob(job)
+	if resp.Status == "ok" {
+		c.Lock()
+		c.responses[name] = resp
+		c.Unlock()
+	}
+	return resp
+}
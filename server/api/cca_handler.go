@@ -0,0 +1,29 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	q "github.com/unchartedsoftware/rannu/cluster/queue"
+)
+
+func ccaHandler(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	job, ok := parseJob(ctx, w)
+	if !ok {
+		return
+	}
+	job.Analysis = q.AnalysisCCA
+
+	query := r.URL.Query()
+	job.Groups = strings.Split(query.Get("groups"), ",")
+	if len(job.Groups) != 2 || job.Groups[0] == "" || job.Groups[0] == job.Groups[1] {
+		log.Printf("Could not parse groups param: %s", query.Get("groups"))
+		http.Error(w, "Could not parse groups param", http.StatusInternalServerError)
+		return
+	}
+
+	runJob(w, job)
+}
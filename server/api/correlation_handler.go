@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http"
+
+	"goji.io/pat"
+
+	"golang.org/x/net/context"
+
+	q "github.com/unchartedsoftware/rannu/cluster/queue"
+)
+
+// correlations caches the correlation matrix and feature clustering of each
+// dataset
+var correlations = newDatasetCache()
+
+func correlationHandler(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	name := pat.Param(ctx, "name")
+	job := &q.Job{
+		Analysis: q.AnalysisCorrelation,
+		Dataset:  name,
+		Workers:  q.StatisticsWorkers(),
+	}
+
+	writeResponse(w, correlations.get(name, job))
+}
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"net/http"
+
+	"golang.org/x/net/context"
+
+	q "github.com/unchartedsoftware/rannu/cluster/queue"
+)
+
+func icaHandler(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	job, ok := parseJob(ctx, w)
+	if !ok {
+		return
+	}
+	job.Analysis = q.AnalysisICA
+	if !parsePCA(w, r, job) {
+		return
+	}
+
+	runJob(w, job)
+}
@@ -0,0 +1,19 @@
+package api
+
+import (
+	"net/http"
+
+	"golang.org/x/net/context"
+
+	q "github.com/unchartedsoftware/rannu/cluster/queue"
+)
+
+func ldaHandler(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	job, ok := parseJob(ctx, w)
+	if !ok {
+		return
+	}
+	job.Analysis = q.AnalysisLDA
+
+	runJob(w, job)
+}
@@ -15,6 +15,172 @@ import (
 )
 
 func pcaHandler(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	job, ok := parseJob(ctx, w)
+	if !ok {
+		return
+	}
+	if !parsePCA(w, r, job) {
+		return
+	}
+
+	runJob(w, job)
+}
+
+// parsePCA sets the principal component options of the job from the query
+// params. It writes an error and returns false if the params are invalid
+func parsePCA(w http.ResponseWriter, r *http.Request, job *q.Job) bool {
+	var err error
+	query := r.URL.Query()
+	job.Mode = query.Get("mode")
+	if job.Mode == "" {
+		job.Mode = q.ModeExact
+	}
+	if !q.ValidMode(job.Mode) {
+		log.Printf("Invalid mode: %s", job.Mode)
+		http.Error(w, "Invalid mode", http.StatusInternalServerError)
+		return false
+	}
+	job.Scaling = query.Get("scaling")
+	if job.Scaling == "" {
+		job.Scaling = q.ScalingSD
+	}
+	if !q.ValidScaling(job.Scaling) || (job.Scaling == q.ScalingRobust && job.Mode == q.ModeEM) {
+		log.Printf("Invalid scaling: %s", job.Scaling)
+		http.Error(w, "Invalid scaling", http.StatusInternalServerError)
+		return false
+	}
+	job.Shrinkage = query.Get("shrinkage")
+	if job.Shrinkage != "" && (!q.ValidShrinkage(job.Shrinkage) || job.Mode != q.ModeExact) {
+		log.Printf("Invalid shrinkage: %s", job.Shrinkage)
+		http.Error(w, "Invalid shrinkage", http.StatusInternalServerError)
+		return false
+	}
+	job.Components = 2
+	if query.Get("components") != "" {
+		job.Components, err = strconv.Atoi(query.Get("components"))
+		if err != nil || job.Components < 2 {
+			log.Printf("Could not parse components param: %s", query.Get("components"))
+			http.Error(w, "Could not parse components param", http.StatusInternalServerError)
+			return false
+		}
+	}
+	job.Tolerance = q.DefaultTolerance
+	if query.Get("tolerance") != "" {
+		job.Tolerance, err = strconv.ParseFloat(query.Get("tolerance"), 64)
+		if err != nil || job.Tolerance <= 0 {
+			log.Printf("Could not parse tolerance param: %s", query.Get("tolerance"))
+			http.Error(w, "Could not parse tolerance param", http.StatusInternalServerError)
+			return false
+		}
+	}
+	job.MaxIterations = q.DefaultMaxIterations
+	if query.Get("iterations") != "" {
+		job.MaxIterations, err = strconv.Atoi(query.Get("iterations"))
+		if err != nil || job.MaxIterations < 1 {
+			log.Printf("Could not parse iterations param: %s", query.Get("iterations"))
+			http.Error(w, "Could not parse iterations param", http.StatusInternalServerError)
+			return false
+		}
+	}
+	if query.Get("cardinality") != "" {
+		job.Cardinality, err = strconv.Atoi(query.Get("cardinality"))
+		if err != nil || job.Cardinality < 1 {
+			log.Printf("Could not parse cardinality param: %s", query.Get("cardinality"))
+			http.Error(w, "Could not parse cardinality param", http.StatusInternalServerError)
+			return false
+		}
+		if job.Mode != q.ModeSparse {
+			log.Printf("Cardinality requires sparse mode: %s", job.Mode)
+			http.Error(w, "Cardinality requires sparse mode", http.StatusInternalServerError)
+			return false
+		}
+	}
+	job.Rotation = query.Get("rotation")
+	if job.Rotation != "" && !q.ValidRotation(job.Rotation) {
+		log.Printf("Invalid rotation: %s", job.Rotation)
+		http.Error(w, "Invalid rotation", http.StatusInternalServerError)
+		return false
+	}
+	if query.Get("parallel") != "" {
+		job.ParallelReplicates, err = strconv.Atoi(query.Get("parallel"))
+		if err != nil || job.ParallelReplicates < 1 {
+			log.Printf("Could not parse parallel param: %s", query.Get("parallel"))
+			http.Error(w, "Could not parse parallel param", http.StatusInternalServerError)
+			return false
+		}
+	}
+	if query.Get("bootstrap") != "" {
+		job.BootstrapReplicates, err = strconv.Atoi(query.Get("bootstrap"))
+		if err != nil || job.BootstrapReplicates < 1 {
+			log.Printf("Could not parse bootstrap param: %s", query.Get("bootstrap"))
+			http.Error(w, "Could not parse bootstrap param", http.StatusInternalServerError)
+			return false
+		}
+	}
+	job.Folds = q.DefaultFolds
+	if query.Get("folds") != "" {
+		job.Folds, err = strconv.Atoi(query.Get("folds"))
+		if err != nil || job.Folds < 2 {
+			log.Printf("Could not parse folds param: %s", query.Get("folds"))
+			http.Error(w, "Could not parse folds param", http.StatusInternalServerError)
+			return false
+		}
+		if job.Mode != q.ModeCrossValidate {
+			log.Printf("Folds require cross-validation mode: %s", job.Mode)
+			http.Error(w, "Folds require cross-validation mode", http.StatusInternalServerError)
+			return false
+		}
+	}
+	// EM jobs return before parallel analysis and bootstrapping
+	if job.Mode == q.ModeEM && (job.ParallelReplicates > 0 || job.BootstrapReplicates > 0) {
+		log.Printf("Parallel analysis and bootstrapping are not supported in EM mode")
+		http.Error(w, "Parallel analysis and bootstrapping are not supported in EM mode", http.StatusInternalServerError)
+		return false
+	}
+	// bootstrap replicates are rescaled by their standard deviations and
+	// neither they nor the permuted replicates are shrunk
+	if job.BootstrapReplicates > 0 && (job.Scaling == q.ScalingRobust || job.Shrinkage != "") {
+		log.Printf("Bootstrapping is not supported with robust scaling or shrinkage")
+		http.Error(w, "Bootstrapping is not supported with robust scaling or shrinkage", http.StatusInternalServerError)
+		return false
+	}
+	if job.ParallelReplicates > 0 && job.Shrinkage != "" {
+		log.Printf("Parallel analysis is not supported with shrinkage")
+		http.Error(w, "Parallel analysis is not supported with shrinkage", http.StatusInternalServerError)
+		return false
+	}
+	if query.Get("clusters") != "" {
+		job.Clusters, err = strconv.Atoi(query.Get("clusters"))
+		if err != nil || job.Clusters < 2 {
+			log.Printf("Could not parse clusters param: %s", query.Get("clusters"))
+			http.Error(w, "Could not parse clusters param", http.StatusInternalServerError)
+			return false
+		}
+	}
+	// clustering and independent components project the rows, which still
+	// hold their missing values in EM mode
+	if job.Mode == q.ModeEM && (job.Clusters > 0 || job.Analysis == q.AnalysisICA) {
+		log.Printf("Clustering and independent components are not supported in EM mode")
+		http.Error(w, "Clustering and independent components are not supported in EM mode", http.StatusInternalServerError)
+		return false
+	}
+	job.ClusterSpace = query.Get("space")
+	if job.ClusterSpace == "" {
+		job.ClusterSpace = q.SpaceRaw
+	}
+	if !q.ValidSpace(job.ClusterSpace) {
+		log.Printf("Invalid cluster space: %s", job.ClusterSpace)
+		http.Error(w, "Invalid cluster space", http.StatusInternalServerError)
+		return false
+	}
+
+	return true
+}
+
+// parseJob builds a job from the dataset, workers and standardize params
+// shared by every endpoint. It writes an error and returns false if the
+// params are invalid
+func parseJob(ctx context.Context, w http.ResponseWriter) (*q.Job, bool) {
 	dataset := pat.Param(ctx, "dataset")
 	workers, err := strconv.Atoi(pat.Param(ctx, "workers"))
 	var standardize bool
@@ -27,25 +193,39 @@ func pcaHandler(ctx context.Context, w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		log.Printf("Could not parse workers param: %s", pat.Param(ctx, "workers"))
 		http.Error(w, "Could not parse workers param", http.StatusInternalServerError)
-		return
+		return nil, false
 	}
 	if workers != 1 && workers != 2 && workers != 4 && workers != 8 {
 		log.Printf("Invalid number of workers: %d", workers)
 		http.Error(w, "Invalid number of workers", http.StatusInternalServerError)
-		return
+		return nil, false
 	}
 
-	respc := make(chan *q.Response)
 	job := &q.Job{
-		Dataset:         dataset,
-		Workers:         workers,
-		Standardize:     standardize,
-		ResponseChannel: respc,
+		Analysis:    q.AnalysisPCA,
+		Dataset:     dataset,
+		Workers:     workers,
+		Standardize: standardize,
 	}
+	return job, true
+}
+
+// runJob queues the job, waits for it to be processed and writes the response
+func runJob(w http.ResponseWriter, job *q.Job) {
+	writeResponse(w, awaitJob(job))
+}
+
+// awaitJob queues the job and returns its response once it is processed
+func awaitJob(job *q.Job) *q.Response {
+	respc := make(chan *q.Response)
+	job.ResponseChannel = respc
 	jobc <- job
 
-	resp := <-respc
+	return <-respc
+}
 
+// writeResponse writes the response of a job as JSON
+func writeResponse(w http.ResponseWriter, resp *q.Response) {
 	body, err := json.Marshal(resp)
 	if err != nil {
 		log.Printf("Unable to marshal response: %v", err)
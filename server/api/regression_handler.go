@@ -0,0 +1,38 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"golang.org/x/net/context"
+
+	q "github.com/unchartedsoftware/rannu/cluster/queue"
+)
+
+func regressionHandler(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	job, ok := parseJob(ctx, w)
+	if !ok {
+		return
+	}
+	job.Analysis = q.AnalysisRegression
+
+	var err error
+	query := r.URL.Query()
+	job.Target, err = strconv.Atoi(query.Get("target"))
+	if err != nil || job.Target < 0 {
+		log.Printf("Could not parse target param: %s", query.Get("target"))
+		http.Error(w, "Could not parse target param", http.StatusInternalServerError)
+		return
+	}
+	if query.Get("ridge") != "" {
+		job.Ridge, err = strconv.ParseFloat(query.Get("ridge"), 64)
+		if err != nil || job.Ridge < 0 {
+			log.Printf("Could not parse ridge param: %s", query.Get("ridge"))
+			http.Error(w, "Could not parse ridge param", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	runJob(w, job)
+}
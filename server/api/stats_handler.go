@@ -0,0 +1,25 @@
+package api
+
+import (
+	"net/http"
+
+	"goji.io/pat"
+
+	"golang.org/x/net/context"
+
+	q "github.com/unchartedsoftware/rannu/cluster/queue"
+)
+
+// statistics caches the descriptive statistics of each dataset
+var statistics = newDatasetCache()
+
+func statsHandler(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	name := pat.Param(ctx, "name")
+	job := &q.Job{
+		Analysis: q.AnalysisStatistics,
+		Dataset:  name,
+		Workers:  q.StatisticsWorkers(),
+	}
+
+	writeResponse(w, statistics.get(name, job))
+}
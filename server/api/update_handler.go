@@ -0,0 +1,22 @@
+package api
+
+import (
+	"net/http"
+
+	"goji.io/pat"
+
+	"golang.org/x/net/context"
+
+	q "github.com/unchartedsoftware/rannu/cluster/queue"
+)
+
+func updateHandler(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	job, ok := parseJob(ctx, w)
+	if !ok {
+		return
+	}
+	job.Mode = q.ModeExact
+	job.Update = pat.Param(ctx, "update")
+
+	runJob(w, job)
+}
@@ -0,0 +1,53 @@
+function heatmap(chartId, labels, resp) {
+  'use strict';
+
+  var margin = {top: 20, right: 20, bottom: 20, left: 160};
+  var size = 600 - margin.left - margin.right;
+
+  // features are laid out in the leaf order of their clustering so that
+  // redundant features form blocks along the diagonal
+  var order = resp.featureOrder;
+  var cell = size / order.length;
+  var cells = [];
+  order.forEach(function(row, i) {
+    order.forEach(function(col, j) {
+      cells.push({i: i, j: j, value: resp.correlation[row][col]});
+    });
+  });
+
+  var color = d3.scale.linear()
+    .domain([-1, 0, 1])
+    .range(['rgb(255, 109, 174)', '#fff', 'rgb(0, 191, 255)']);
+
+  d3.select(chartId).selectAll('*').remove();
+  var svg = d3.select(chartId)
+    .append('svg')
+    .attr('width', size + margin.left + margin.right)
+    .attr('height', size + margin.top + margin.bottom)
+    .append('g')
+    .attr('transform', 'translate(' + margin.left + ',' + margin.top + ')');
+
+  svg.selectAll('.cell')
+    .data(cells)
+    .enter().append('rect')
+    .attr('class', 'cell')
+    .attr('x', function(d) { return d.j * cell; })
+    .attr('y', function(d) { return d.i * cell; })
+    .attr('width', cell)
+    .attr('height', cell)
+    .style('fill', function(d) { return color(d.value); })
+    .append('title')
+    .text(function(d) {
+      return labels[order[d.i]] + ' / ' + labels[order[d.j]] + ': ' + d.value.toFixed(3);
+    });
+
+  svg.selectAll('.label')
+    .data(order)
+    .enter().append('text')
+    .attr('class', 'label')
+    .attr('x', -6)
+    .attr('y', function(d, i) { return (i + 0.5) * cell; })
+    .attr('dy', '.35em')
+    .style('text-anchor', 'end')
+    .text(function(d) { return labels[d]; });
+}
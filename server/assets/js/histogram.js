@@ -0,0 +1,76 @@
+function histogram(chartId, label, summary) {
+  'use strict';
+
+  var margin = {top: 20, right: 20, bottom: 45, left: 65};
+  var width = 600 - margin.left - margin.right;
+  var height = 250 - margin.top - margin.bottom;
+
+  var counts = summary.histogram || [];
+  var binWidth = (summary.max - summary.min) / Math.max(counts.length, 1);
+  var bins = counts.map(function(count, i) {
+    return {x: summary.min + i * binWidth, count: count};
+  });
+
+  var xScale = d3.scale.linear()
+    .domain([summary.min, summary.max === summary.min ? summary.min + 1 : summary.max])
+    .range([0, width]);
+  var xAxis = d3.svg.axis()
+    .ticks(5)
+    .scale(xScale)
+    .orient('bottom');
+
+  var yScale = d3.scale.linear()
+    .domain([0, d3.max(counts) || 1])
+    .range([height, 0]);
+  var yAxis = d3.svg.axis()
+    .ticks(5)
+    .scale(yScale)
+    .orient('left');
+
+  d3.select(chartId).selectAll('*').remove();
+  var svg = d3.select(chartId)
+    .append('svg')
+    .attr('width', width + margin.left + margin.right)
+    .attr('height', height + margin.top + margin.bottom)
+    .append('g')
+    .attr('transform', 'translate(' + margin.left + ',' + margin.top + ')');
+
+  svg.append('g')
+    .attr('class', 'x axis')
+    .attr('transform', 'translate(0,' + height + ')')
+    .call(xAxis)
+    .append('text')
+    .attr('x', width / 2)
+    .attr('y', 35)
+    .style('text-anchor', 'middle')
+    .text(label);
+
+  svg.append('g')
+    .attr('class', 'y axis')
+    .call(yAxis)
+    .append('text')
+    .attr('transform', 'rotate(-90)')
+    .attr('x', -height / 2)
+    .attr('y', -50)
+    .style('text-anchor', 'middle')
+    .text('Weight');
+
+  svg.selectAll('.bar')
+    .data(bins)
+    .enter().append('rect')
+    .attr('class', 'bar')
+    .attr('x', function(d) { return xScale(d.x); })
+    .attr('width', Math.max(xScale(summary.min + binWidth) - xScale(summary.min) - 1, 1))
+    .attr('y', function(d) { return yScale(d.count); })
+    .attr('height', function(d) { return height - yScale(d.count); });
+
+  // mark the median and the quartiles estimated from the merged sketches
+  svg.selectAll('.quartile')
+    .data([summary.q1, summary.median, summary.q3])
+    .enter().append('line')
+    .attr('class', function(d, i) { return i === 1 ? 'quartile median' : 'quartile'; })
+    .attr('x1', xScale)
+    .attr('x2', xScale)
+    .attr('y1', 0)
+    .attr('y2', height);
+}
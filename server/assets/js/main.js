@@ -3,6 +3,9 @@
 
   var dataset = $('#dataset');
   var workers = $('#workers');
+  var analysis = $('#analysis');
+  var clusters = $('#clusters');
+  var space = $('#space');
   //var standardize = $('#standardize');
   var title = $('#title');
   var results = $('#results');
@@ -17,18 +20,105 @@
     iris: $('#iris-footer')
   };
 
+  function populateStats(table, resp) {
+    var fields = ['mean', 'sd', 'min', 'max', 'median', 'skewness', 'kurtosis', 'missing'];
+    table.find('tbody tr').each(function(i) {
+      var row = $(this);
+      var summary = resp.statistics[i];
+      fields.forEach(function(field) {
+        row.find('.' + field).html(summary[field]);
+      });
+      row.find('.iqr').html(summary.q3 - summary.q1);
+      // clicking a feature shows the histogram of its values
+      row.off('click').click(function() {
+        histogram('#histogram', row.children().first().text(), summary);
+      });
+    });
+  }
+
+  // show the descriptive statistics of a dataset as soon as it is selected
+  dataset.change(function() {
+    var name = dataset.val();
+    var selected = name === 'credit-card' ? table.creditCard : table.iris;
+    if (!name) {
+      return;
+    }
+    $('#histogram').empty();
+    $('#heatmap').empty();
+    $.get('/api/datasets/' + name + '/stats', function(resp) {
+      if (resp.status !== 'ok') {
+        alert('Uh oh! ' + resp.message);
+        return;
+      }
+      $('.table').hide();
+      populateStats(selected, resp);
+      selected.show();
+    });
+    $.get('/api/datasets/' + name + '/correlation', function(resp) {
+      if (resp.status !== 'ok') {
+        alert('Uh oh! ' + resp.message);
+        return;
+      }
+      var labels = selected.find('tbody tr').map(function() {
+        return $(this).children().first().text();
+      }).get();
+      heatmap('#heatmap', labels, resp);
+    });
+  });
+
+  function axisName() {
+    return analysis.val() === 'lda' ? 'Discriminant' : 'Principal Component';
+  }
+
   function populateTable(table, resp) {
-    var combined = _.zip(resp.eigenvalues, resp.eigenvectors);
-    var sorted = _.sortBy(combined, function(el) { return el[0]; });
-    var pc1 = sorted.pop();
-    var pc2 = sorted.pop();
+    // eigenvectors arrive sorted by descending eigenvalue
+    var pc1 = resp.eigenvectors[0];
+    var pc2 = resp.eigenvectors[1];
+    table.find('.pc1-title').text(axisName() + ' 1');
+    table.find('.pc2-title').text(axisName() + ' 2');
     table.find('tbody tr').each(function(i) {
       var row = $(this);
-      row.find('.pc1').html(pc1[1][i]);
-      row.find('.pc2').html(pc2[1][i]);
+      row.find('.pc1').html(pc1[i]);
+      row.find('.pc2').html(pc2[i]);
     });
   }
 
+  function clusterParams() {
+    if (!clusters.val()) {
+      return '';
+    }
+    return '?clusters=' + clusters.val() + '&space=' + space.val();
+  }
+
+  function endpoint(name, numWorkers, standardized) {
+    if (analysis.val() === 'lda') {
+      return '/api/lda/' + name + '/' + numWorkers + '/' + standardized;
+    }
+    return '/api/pca/' + name + '/' + numWorkers + '/' + standardized + clusterParams();
+  }
+
+  // LDA jobs return the scores of every row while PCA plots the saved scores
+  function points(resp, dataFile) {
+    if (!resp.scores) {
+      return dataFile;
+    }
+    return resp.scores.map(function(row) {
+      return {pc1: row[0], pc2: row[1], value: row[2]};
+    });
+  }
+
+  function summary(resp, standardized) {
+    var share = analysis.val() === 'lda' ? 'Percent of Separation: ' : 'Percent of Variance: ';
+    return '<p>Elapsed time: ' + resp.elapsed + ' seconds</p><p>' + share + resp.percentVariance + '%</p><p>Standardized: ' + standardized + '</p>' + clusterResults(resp);
+  }
+
+  function clusterResults(resp) {
+    if (!resp.centroids) {
+      return '';
+    }
+    return '<p>Clusters: ' + resp.centroids.length + ' (sizes ' + resp.clusterSizes.join(', ') + ', ' + resp.clusterIterations + ' iterations)</p>';
+  }
+
   $('#submit').click(function() {
     var numWorkers = workers.val();
     if (!numWorkers) {
@@ -49,15 +139,15 @@
     switch (dataset.val()) {
     case 'credit-card':
       title.text('Credit Card Defaults');
-      $.get('/api/pca/credit-card/' + numWorkers + '/true', function(resp) {
+      $.get(endpoint('credit-card', numWorkers, true), function(resp) {
         if (resp.status !== 'ok') {
           alert('Uh oh! ' + resp.message);
           return;
         }
-        results.html('<p>Elapsed time: ' + resp.elapsed + ' seconds</p><p>Percent of Variance: ' + resp.percentVariance + '%</p><p>Standardized: Yes</p>');
+        results.html(summary(resp, 'Yes'));
         //dataFile = standardized === "true" ? 'credit-card-standardized.csv' : 'credit-card.csv';
         dataFile = 'credit-card-standardized.csv';
-        scatter('#scatter', '#loading', dataFile, ['No Default', 'Default']);
+        scatter('#scatter', '#loading', points(resp, dataFile), ['No Default', 'Default'], resp.centroids, axisName());
         $('.table').hide();
         populateTable(table.creditCard, resp);
         table.creditCard.show();
@@ -67,16 +157,16 @@
       break;
     case 'iris':
       title.text('Iris');
-      $.get('/api/pca/iris/' + numWorkers + '/false', function(resp) {
+      $.get(endpoint('iris', numWorkers, false), function(resp) {
         if (resp.status !== 'ok') {
           console.log(resp);
           alert('Uh oh! ' + resp.message);
           return;
         }
-        results.html('<p>Elapsed time: ' + resp.elapsed + ' seconds</p><p>Percent of Variance: ' + resp.percentVariance + '%</p><p>Standardized: No</p>');
+        results.html(summary(resp, 'No'));
         //dataFile = standardized === "true" ? 'iris-standardized.csv' : 'iris.csv';
         dataFile = 'iris.csv';
-        scatter('#scatter', '#loading', dataFile, ['Setosa', 'Versicolor', 'Virginica']);
+        scatter('#scatter', '#loading', points(resp, dataFile), ['Setosa', 'Versicolor', 'Virginica'], resp.centroids, axisName());
         $('.table').hide();
         populateTable(table.iris, resp);
         table.iris.show();
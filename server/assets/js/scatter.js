@@ -1,4 +1,4 @@
-function scatter(chartId, loadingId, filename, labels) {
+function scatter(chartId, loadingId, source, labels, centroids, axis) {
   'use strict';
 
   var margin = {top: 20, right: 20, bottom: 65, left: 65};
@@ -50,13 +50,8 @@ function scatter(chartId, loadingId, filename, labels) {
     .append('g')
     .attr('transform', 'translate(' + margin.left + ',' + margin.top + ')');
 
-  d3.csv(filename, function(error, data) {
-    data.forEach(function(d) {
-      d.pc1 = +d.pc1;
-      d.pc2 = +d.pc2;
-      d.value = +d.value;
-    });
-
+  // source is either the name of a CSV file of saved scores or the points
+  function draw(data) {
     d3.select(loadingId)
       .style('display', 'none');
 
@@ -72,7 +67,7 @@ function scatter(chartId, loadingId, filename, labels) {
       .attr('x', width)
       .attr('y', -6)
       .style('text-anchor', 'end')
-      .text('Principal Component 1');
+      .text((axis || 'Principal Component') + ' 1');
 
     svg.append('g')
       .attr('class', 'y axis')
@@ -83,7 +78,7 @@ function scatter(chartId, loadingId, filename, labels) {
       .attr('y', 6)
       .attr('dy', '.71em')
       .style('text-anchor', 'end')
-      .text('Principal Component 2');
+      .text((axis || 'Principal Component') + ' 2');
 
     svg.selectAll('.dot')
       .data(data)
@@ -94,6 +89,17 @@ function scatter(chartId, loadingId, filename, labels) {
       .attr('cx', xMap)
       .attr('cy', yMap);
 
+    // centroids arrive as [pc1, pc2] pairs when the job clustered the rows
+    svg.selectAll('.centroid')
+      .data(centroids || [])
+      .enter()
+      .append('path')
+      .attr('class', 'centroid')
+      .attr('d', d3.svg.symbol().type('cross').size(120))
+      .attr('transform', function(d) {
+        return 'translate(' + xScale(d[0]) + ',' + yScale(d[1]) + ')';
+      });
+
     var legend = svg.selectAll('.legend')
       .data(labels)
       .enter()
@@ -112,5 +118,18 @@ function scatter(chartId, loadingId, filename, labels) {
       .attr('dy', '.35em')
       .style('text-anchor', 'end')
       .text(function(d) { return d; })
+  }
+
+  if (typeof source !== 'string') {
+    draw(source);
+    return;
+  }
+  d3.csv(source, function(error, data) {
+    data.forEach(function(d) {
+      d.pc1 = +d.pc1;
+      d.pc2 = +d.pc2;
+      d.value = +d.value;
+    });
+    draw(data);
   });
 }
@@ -7,11 +7,14 @@ import (
 	"log"
 	"net/http"
 	"runtime"
+	"strconv"
+	"strings"
 
 	"goji.io"
 
 	"goji.io/pat"
 
+	q "github.com/unchartedsoftware/rannu/cluster/queue"
 	"github.com/unchartedsoftware/rannu/server/api"
 )
 
@@ -37,6 +40,8 @@ var (
 		"worker7:7901", "Worker 7 address")
 	addr8 = flag.CommandLine.String("addr8",
 		"worker8:7901", "Worker 8 address")
+	weights = flag.CommandLine.String("weights",
+		"", "Comma separated dataset:column pairs marking the weight column of a dataset")
 )
 
 func indexHandler(w http.ResponseWriter, r *http.Request) {
@@ -97,6 +102,20 @@ func main() {
 		log.Fatal(err)
 	}
 
+	if *weights != "" {
+		for _, pair := range strings.Split(*weights, ",") {
+			parts := strings.Split(pair, ":")
+			if len(parts) != 2 {
+				log.Fatalf("Invalid weight column: %s", pair)
+			}
+			column, err := strconv.Atoi(parts[1])
+			if err != nil || column < 0 {
+				log.Fatalf("Invalid weight column: %s", pair)
+			}
+			q.SetWeightColumn(parts[0], column)
+		}
+	}
+
 	mux := goji.NewMux()
 	mux.HandleFunc(pat.Get("/"), indexHandler)
 
@@ -115,6 +134,7 @@ func main() {
 		log.Fatal(err)
 	}
 	mux.Handle(pat.Get("/api/*"), apiMux)
+	mux.Handle(pat.Post("/api/*"), apiMux)
 
 	mux.Handle(pat.Get("/*"), http.FileServer(http.Dir("assets")))
 